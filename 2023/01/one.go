@@ -1,60 +1,105 @@
-package main
+package day01
 
 import (
-	_ "embed"
-	"fmt"
-	"strconv"
+	"errors"
+	"log/slog"
+	"maps"
 	"strings"
+
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
-//go:embed input.txt
-var input string
+func init() {
+	registry.Register(2023, 1, registry.Solver[[]parse.Token]{
+		Parse: parseLines,
+		Part1: func(lines []parse.Token) (any, error) { return sumCalibrationValues(lines, Digits) },
+		Part2: func(lines []parse.Token) (any, error) { return sumCalibrationValues(lines, English) },
+	})
+}
 
-func main() {
-	lines := strings.Split(input, "\n")
-	calibrationValues := findCalibrationValues(lines)
-	sum := 0
-	for _, calibrationValue := range calibrationValues {
-		sum += calibrationValue
+// ErrNoDigit is returned when looking for the calibration value of a line without any digit.
+var ErrNoDigit = errors.New("no digit")
+
+// Dictionary associates the tokens spelling digits to the values of these digits.
+type Dictionary map[string]int
+
+var (
+	// Digits is the dictionary of the digits written as such.
+	Digits = Dictionary{"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9}
+	// English is the dictionary of the digits, either written as such or spelled out in English.
+	English = Digits.With(Dictionary{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7,
+		"eight": 8, "nine": 9})
+	// French is the dictionary of the digits, either written as such or spelled out in French.
+	French = Digits.With(Dictionary{"un": 1, "deux": 2, "trois": 3, "quatre": 4, "cinq": 5, "six": 6, "sept": 7,
+		"huit": 8, "neuf": 9})
+)
+
+// With returns a new dictionary with the tokens of this dictionary and the given ones.
+func (d Dictionary) With(tokens Dictionary) Dictionary {
+	merged := maps.Clone(d)
+	maps.Copy(merged, tokens)
+	return merged
+}
+
+// FirstDigit returns the value of the first digit token of the given line, and whether there is one.
+func (d Dictionary) FirstDigit(line string) (int, bool) {
+	for i := range len(line) {
+		if digit, found := d.digitAt(line, i); found {
+			return digit, true
+		}
 	}
-	fmt.Println(sum)
+	return 0, false
 }
 
-func findCalibrationValues(lines []string) []int {
-	calibrationValues := make([]int, 0, len(lines))
-	for _, line := range lines {
-		calibrationValue := findCalibrationValue(line)
-		calibrationValues = append(calibrationValues, calibrationValue)
-		fmt.Printf("%v -> %v\n", line, calibrationValue)
+// LastDigit returns the value of the last digit token of the given line, and whether there is one. Tokens may overlap:
+// the last digit of "eightwo" is 2 in English, even though its first digit, 8, ends on the "t" of "two".
+func (d Dictionary) LastDigit(line string) (int, bool) {
+	for i := len(line) - 1; i >= 0; i-- {
+		if digit, found := d.digitAt(line, i); found {
+			return digit, true
+		}
+	}
+	return 0, false
+}
+
+// digitAt returns the value of the longest digit token starting at the given index of the line, if any.
+func (d Dictionary) digitAt(line string, i int) (int, bool) {
+	digit, longest := 0, 0
+	for token, value := range d {
+		if len(token) > longest && strings.HasPrefix(line[i:], token) {
+			digit, longest = value, len(token)
+		}
 	}
-	return calibrationValues
+	return digit, longest > 0
 }
 
-func findCalibrationValue(line string) int {
-	digits := findDigits(line)
-	return digits[0]*10 + digits[len(digits)-1]
+func parseLines(input string) ([]parse.Token, error) {
+	return parse.Lines(input), nil
 }
 
-func findDigits(line string) []int {
-	preparedLine := strings.ReplaceAll(line, "one", "o1e")
-	preparedLine = strings.ReplaceAll(preparedLine, "two", "t2o")
-	preparedLine = strings.ReplaceAll(preparedLine, "three", "t3e")
-	preparedLine = strings.ReplaceAll(preparedLine, "four", "4")
-	preparedLine = strings.ReplaceAll(preparedLine, "five", "5e")
-	preparedLine = strings.ReplaceAll(preparedLine, "six", "6")
-	preparedLine = strings.ReplaceAll(preparedLine, "seven", "7n")
-	preparedLine = strings.ReplaceAll(preparedLine, "eight", "8t")
-	preparedLine = strings.ReplaceAll(preparedLine, "nine", "n9e")
-	return findDigitsPart1(preparedLine)
+// CalibrationValue returns the calibration value of the given line, made of its first and last digits according to
+// the given dictionary, or ErrNoDigit if the line has no digit.
+func CalibrationValue(line parse.Token, d Dictionary) (int, error) {
+	first, found := d.FirstDigit(line.Text)
+	if !found {
+		return 0, line.Errorf("%w", ErrNoDigit)
+	}
+	last, _ := d.LastDigit(line.Text)
+	return first*10 + last, nil
 }
 
-func findDigitsPart1(line string) []int {
-	digits := make([]int, 0, 2)
-	for _, r := range []rune(line) {
-		n, err := strconv.Atoi(string(r))
-		if err == nil {
-			digits = append(digits, n)
+// sumCalibrationValues returns the sum of the calibration values of the given lines, or an error if a line has no
+// digit.
+func sumCalibrationValues(lines []parse.Token, d Dictionary) (int, error) {
+	sum := 0
+	for _, line := range lines {
+		calibrationValue, err := CalibrationValue(line, d)
+		if err != nil {
+			return 0, err
 		}
+		slog.Debug("found calibration value", "line", line.Text, "value", calibrationValue)
+		sum += calibrationValue
 	}
-	return digits
+	return sum, nil
 }
@@ -0,0 +1,53 @@
+package day01
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/parse"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 1)
+}
+
+func TestCalibrationValue(t *testing.T) {
+	tests := []struct {
+		line       string
+		dictionary Dictionary
+		expected   int
+	}{
+		{"treb7uchet", Digits, 77},
+		{"eightwothree", English, 83},
+		{"eightwo", English, 82},
+		{"zoneight234", English, 14},
+		{"oneight", English, 18},
+		{"troisept", French, 37},
+		{"xhuitrois9unze", French, 81},
+	}
+	for _, test := range tests {
+		actual, err := CalibrationValue(parse.Token{Text: test.line}, test.dictionary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.line, test.expected, actual)
+		}
+	}
+}
+
+func TestCalibrationValueWithoutDigit(t *testing.T) {
+	lines := parse.Lines("1abc2\nabcdef\n")
+	_, err := CalibrationValue(lines[1], English)
+	if !errors.Is(err, ErrNoDigit) {
+		t.Fatalf("expected ErrNoDigit, got %v", err)
+	}
+	if context := parse.Context("1abc2\nabcdef\n", err); context != "2 | abcdef\n  | ^\n" {
+		t.Errorf("expected the error to be located on line 2, got %q", context)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 1)
+}
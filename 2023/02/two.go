@@ -1,121 +1,284 @@
-package main
+package day02
 
 import (
-	_ "embed"
 	"fmt"
-	"regexp"
-	"strconv"
+	"log/slog"
+	"maps"
+	"slices"
 	"strings"
-)
 
-const (
-	redMax   = 12
-	greenMax = 13
-	blueMax  = 14
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
 )
 
-//go:embed input.txt
-var input string
-var grabRe = regexp.MustCompile(`(?:(?P<count>\d+) (?P<color>red|green|blue),?){1,3}`)
-var gameRe = regexp.MustCompile(`Game (?P<id>\d+):(?P<grabs>.*)`)
+// Color is the name of a color of cubes, e.g. "red".
+type Color string
+
+// Cubes counts cubes by color: the cubes of a grab, or the cubes in a bag.
+type Cubes map[Color]int
+
+// ElfBag is the bag of the elf of the puzzle, the one used to solve part 1.
+var ElfBag = Cubes{"red": 12, "green": 13, "blue": 14}
+
+// ParseCubes parses cubes written like the grabs of the puzzle, e.g. "12 red, 13 green, 14 blue".
+func ParseCubes(input string) (Cubes, error) {
+	return parseCubes(parse.Token{Text: input, Line: 1, Column: 1})
+}
+
+// Contains returns true if all the given cubes can be taken from these ones.
+func (c Cubes) Contains(other Cubes) bool {
+	for color, count := range other {
+		if count > c[color] {
+			return false
+		}
+	}
+	return true
+}
+
+// Power returns the product of the counts of the given colors.
+func (c Cubes) Power(colors []Color) int {
+	power := 1
+	for _, color := range colors {
+		power *= c[color]
+	}
+	return power
+}
 
-type Grab struct {
-	red, blue, green int
+// Colors returns the colors of the cubes, sorted.
+func (c Cubes) Colors() []Color {
+	return slices.Sorted(maps.Keys(c))
+}
+
+func (c Cubes) String() string {
+	counts := make([]string, 0, len(c))
+	for _, color := range c.Colors() {
+		counts = append(counts, fmt.Sprintf("%d %s", c[color], color))
+	}
+	return strings.Join(counts, ", ")
 }
 
 type Game struct {
 	id    int
-	grabs []Grab
+	grabs []Cubes
 }
 
-func (game *Game) isPossible() bool {
+func (game *Game) isPossible(bag Cubes) bool {
 	for _, grab := range game.grabs {
-		if grab.red > redMax || grab.blue > blueMax || grab.green > greenMax {
+		if !bag.Contains(grab) {
 			return false
 		}
 	}
 	return true
 }
 
-func (game *Game) minimumSet() (red, green, blue int) {
+// minimumSet returns the fewest cubes of each color the bag must contain for the game to be possible.
+func (game *Game) minimumSet() Cubes {
+	minimum := Cubes{}
 	for _, grab := range game.grabs {
-		if grab.red > red {
-			red = grab.red
-		}
-		if grab.blue > blue {
-			blue = grab.blue
-		}
-		if grab.green > green {
-			green = grab.green
+		for color, count := range grab {
+			minimum[color] = max(minimum[color], count)
 		}
 	}
-	return
+	return minimum
+}
+
+// Excess is a grab of more cubes of a color than the bag contains.
+type Excess struct {
+	Grab  int
+	Color Color
+	Count int
+	Limit int
+}
+
+// Feasibility explains whether a game is possible with a given bag: it is if it has no excess.
+type Feasibility struct {
+	Game     int
+	Excesses []Excess
 }
 
-func (game *Game) minimumPowerSet() int {
-	red, green, blue := game.minimumSet()
-	return red * green * blue
+// Possible returns true if the game is possible.
+func (f Feasibility) Possible() bool {
+	return len(f.Excesses) == 0
+}
+
+// String returns the explanation, e.g. "game 3 is impossible: grab 1 has 20 red, bag has 12".
+func (f Feasibility) String() string {
+	if f.Possible() {
+		return fmt.Sprintf("game %d is possible", f.Game)
+	}
+	reasons := make([]string, len(f.Excesses))
+	for i, excess := range f.Excesses {
+		reasons[i] = fmt.Sprintf("grab %d has %d %s, bag has %d", excess.Grab, excess.Count, excess.Color, excess.Limit)
+	}
+	return fmt.Sprintf("game %d is impossible: %s", f.Game, strings.Join(reasons, "; "))
 }
 
-func main() {
-	games := parseGames(input)
+// Explain returns why the game is possible or not with the given bag. Grabs are numbered from 1.
+func (game *Game) Explain(bag Cubes) Feasibility {
+	feasibility := Feasibility{Game: game.id}
+	for i, grab := range game.grabs {
+		for _, color := range grab.Colors() {
+			if grab[color] > bag[color] {
+				feasibility.Excesses = append(feasibility.Excesses, Excess{i + 1, color, grab[color], bag[color]})
+			}
+		}
+	}
+	return feasibility
+}
+
+// Games is the record of the games played with the elf.
+type Games []Game
+
+// PossibleIdSum returns the sum of the identifiers of the games which are possible with the given bag.
+func (games Games) PossibleIdSum(bag Cubes) int {
 	possibleGameIdSum := 0
-	minimumPowerSetSum := 0
 	for _, game := range games {
-		fmt.Printf("%+v -> %v, minimum power set = %v\n", game, game.isPossible(), game.minimumPowerSet())
-		if game.isPossible() {
+		if report.Enabled(slog.LevelDebug) {
+			slog.Debug("checked game", "feasibility", game.Explain(bag))
+		}
+		if game.isPossible(bag) {
 			possibleGameIdSum += game.id
 		}
-		minimumPowerSetSum += game.minimumPowerSet()
 	}
-	fmt.Printf("Possible game id sum: %v\n", possibleGameIdSum)
-	fmt.Printf("Minimum power set sum: %v\n", minimumPowerSetSum)
+	return possibleGameIdSum
 }
 
-func parseGames(input string) []Game {
-	lines := strings.Split(input, "\n")
-	games := make([]Game, 0, len(lines))
+// MinimumPowerSetSum returns the sum of the powers of the minimum sets of the games, over all the colors of the games.
+func (games Games) MinimumPowerSetSum() int {
+	colors := games.MinimalBag().Colors()
+	minimumPowerSetSum := 0
+	for _, game := range games {
+		minimumPowerSetSum += game.minimumSet().Power(colors)
+	}
+	return minimumPowerSetSum
+}
+
+// MinimalBag returns the bag with the fewest cubes making all the games possible.
+func (games Games) MinimalBag() Cubes {
+	bag := Cubes{}
+	for _, game := range games {
+		for color, count := range game.minimumSet() {
+			bag[color] = max(bag[color], count)
+		}
+	}
+	return bag
+}
+
+// LimitingColors returns, for each color, the number of games made impossible by the given bag not containing enough
+// cubes of this color. Colors limiting no game are absent.
+func (games Games) LimitingColors(bag Cubes) map[Color]int {
+	limiting := make(map[Color]int)
+	for _, game := range games {
+		for color, count := range game.minimumSet() {
+			if count > bag[color] {
+				limiting[color]++
+			}
+		}
+	}
+	return limiting
+}
+
+// MostLimitingColor returns the color limiting the most games with the given bag, the first one by name in case of
+// tie, and the number of games it limits. It returns false if all the games are possible.
+func (games Games) MostLimitingColor(bag Cubes) (Color, int, bool) {
+	limiting := games.LimitingColors(bag)
+	if len(limiting) == 0 {
+		return "", 0, false
+	}
+	colors := slices.Sorted(maps.Keys(limiting))
+	mostLimiting := colors[0]
+	for _, color := range colors[1:] {
+		if limiting[color] > limiting[mostLimiting] {
+			mostLimiting = color
+		}
+	}
+	return mostLimiting, limiting[mostLimiting], true
+}
+
+// Explain returns why each game is possible or not with the given bag.
+func (games Games) Explain(bag Cubes) []Feasibility {
+	feasibilities := make([]Feasibility, len(games))
+	for i, game := range games {
+		feasibilities[i] = game.Explain(bag)
+	}
+	return feasibilities
+}
+
+func init() {
+	registry.Register(2023, 2, registry.Solver[Games]{
+		Parse: parseGames,
+		// the bag is always ElfBag when solving: other bags, e.g. read with ParseCubes, can only be checked by calling
+		// PossibleIdSum directly
+		Part1: func(games Games) (any, error) { return games.PossibleIdSum(ElfBag), nil },
+		Part2: func(games Games) (any, error) { return games.MinimumPowerSetSum(), nil },
+	})
+}
+
+func parseGames(input string) (Games, error) {
+	lines := parse.Lines(input)
+	games := make(Games, 0, len(lines))
 	for _, line := range lines {
-		game := parseGame(line)
+		game, err := parseGame(line)
+		if err != nil {
+			return nil, err
+		}
 		games = append(games, game)
 	}
-	return games
+	return games, nil
 }
 
-func parseGame(line string) Game {
-	matches := gameRe.FindStringSubmatch(line)
-	id, _ := strconv.Atoi(matches[gameRe.SubexpIndex("id")])
-	grabs := parseGrabs(matches[gameRe.SubexpIndex("grabs")])
+func parseGame(line parse.Token) (Game, error) {
+	header, grabsToken, err := line.Cut(":")
+	if err != nil {
+		return Game{}, err
+	}
+	idToken, err := header.TrimPrefix("Game ")
+	if err != nil {
+		return Game{}, err
+	}
+	id, err := idToken.Int()
+	if err != nil {
+		return Game{}, err
+	}
+	grabs, err := parseGrabs(grabsToken)
+	if err != nil {
+		return Game{}, err
+	}
 	return Game{
 		id,
 		grabs,
-	}
+	}, nil
 }
 
-func parseGrabs(line string) []Grab {
-	grabs := make([]Grab, 0)
-	for _, grab := range strings.Split(line, ";") {
-		grabs = append(grabs, parseGrab(grab))
+func parseGrabs(grabsToken parse.Token) ([]Cubes, error) {
+	grabs := make([]Cubes, 0)
+	for _, grabToken := range grabsToken.Split(";") {
+		grab, err := parseCubes(grabToken)
+		if err != nil {
+			return nil, err
+		}
+		grabs = append(grabs, grab)
 	}
-	return grabs
+	return grabs, nil
 }
 
-func parseGrab(grabStr string) Grab {
-	grab := grabRe.FindAllStringSubmatch(grabStr, -1)
-	var red, blue, green int
-	for _, oneColor := range grab {
-		switch oneColor[grabRe.SubexpIndex("color")] {
-		case "red":
-			red, _ = strconv.Atoi(oneColor[grabRe.SubexpIndex("count")])
-		case "blue":
-			blue, _ = strconv.Atoi(oneColor[grabRe.SubexpIndex("count")])
-		case "green":
-			green, _ = strconv.Atoi(oneColor[grabRe.SubexpIndex("count")])
+func parseCubes(cubesToken parse.Token) (Cubes, error) {
+	cubes := Cubes{}
+	for _, oneColor := range cubesToken.Split(",") {
+		fields, err := oneColor.TrimSpace().ExpectFields(2)
+		if err != nil {
+			return nil, err
 		}
+		count, err := fields[0].Int()
+		if err != nil {
+			return nil, err
+		}
+		color := Color(fields[1].Text)
+		if _, duplicate := cubes[color]; duplicate {
+			return nil, fields[1].Errorf("duplicate color %q", color)
+		}
+		cubes[color] = count
 	}
-	return Grab{
-		red,
-		blue,
-		green,
-	}
+	return cubes, nil
 }
@@ -0,0 +1,77 @@
+package day02
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 2)
+}
+
+func TestRuntimeBag(t *testing.T) {
+	games := aoctest.ParseExample(t, parseGames)
+	bag, err := ParseCubes("20 red, 13 green, 15 blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := games.PossibleIdSum(bag); sum != 15 {
+		t.Errorf("expected 15, got %d", sum)
+	}
+	if _, err := ParseCubes("3 red, 4 red"); err == nil {
+		t.Error("expected an error for a duplicate color")
+	}
+}
+
+func TestArbitraryColors(t *testing.T) {
+	games, err := parseGames("Game 1: 3 purple, 1 red; 2 purple\nGame 2: 5 purple\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := games.PossibleIdSum(Cubes{"purple": 4, "red": 1}); sum != 1 {
+		t.Errorf("expected 1, got %d", sum)
+	}
+	if sum := games.MinimumPowerSetSum(); sum != 3 { // game 2 has no red cube, its power is 0
+		t.Errorf("expected 3, got %d", sum)
+	}
+}
+
+func TestMinimalBag(t *testing.T) {
+	expected := Cubes{"red": 20, "green": 13, "blue": 15}
+	if actual := aoctest.ParseExample(t, parseGames).MinimalBag(); !maps.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestMostLimitingColor(t *testing.T) {
+	games := aoctest.ParseExample(t, parseGames)
+	color, count, found := games.MostLimitingColor(ElfBag)
+	if !found || color != "red" || count != 2 {
+		t.Errorf("expected red limiting 2 games, got %q limiting %d games", color, count)
+	}
+	if _, _, found := games.MostLimitingColor(games.MinimalBag()); found {
+		t.Error("expected no limiting color with the minimal bag")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	feasibilities := aoctest.ParseExample(t, parseGames).Explain(ElfBag)
+	expected := []string{
+		"game 1 is possible",
+		"game 2 is possible",
+		"game 3 is impossible: grab 1 has 20 red, bag has 12",
+		"game 4 is impossible: grab 3 has 15 blue, bag has 14; grab 3 has 14 red, bag has 12",
+		"game 5 is possible",
+	}
+	for i, feasibility := range feasibilities {
+		if feasibility.String() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], feasibility)
+		}
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 2)
+}
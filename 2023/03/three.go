@@ -1,204 +1,299 @@
-package main
+package day03
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
-	"strconv"
 	"strings"
+
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
 )
 
 const (
 	emptySymbol = '.'
 	gearSymbol  = '*'
+	// noSlot marks the cells of the slot index covered by no number slot.
+	noSlot = -1
 )
 
-var digits = []rune{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'}
-
-type Pos struct {
-	rowIndex int
-	colIndex int
-}
+var digits = []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'}
 
 type NumberSlot struct {
 	rowIndex      int
 	colStartIndex int
 	colEndIndex   int
+	value         int
 }
 
-func (ns *NumberSlot) NeighborPositions(schema *Schema) []Pos {
-	// XXXXX
-	// X123X
-	// XXXXX
-	rowCount := schema.RowCount()
-	colCount := schema.ColCount()
-	neighborPositions := make([]Pos, 0)
-	if ns.rowIndex > 0 {
-		for colIndex := max(ns.colStartIndex-1, 0); colIndex <= min(ns.colEndIndex, colCount-1); colIndex++ {
-			neighborPositions = append(neighborPositions, Pos{rowIndex: ns.rowIndex - 1, colIndex: colIndex})
-		}
-	}
-	if ns.colStartIndex > 0 {
-		neighborPositions = append(neighborPositions, Pos{rowIndex: ns.rowIndex, colIndex: ns.colStartIndex - 1})
-	}
-	if ns.colEndIndex < colCount-1 {
-		neighborPositions = append(neighborPositions, Pos{rowIndex: ns.rowIndex, colIndex: ns.colEndIndex})
-	}
-	if ns.rowIndex < rowCount-1 {
-		for colIndex := max(ns.colStartIndex-1, 0); colIndex <= min(ns.colEndIndex, colCount-1); colIndex++ {
-			neighborPositions = append(neighborPositions, Pos{rowIndex: ns.rowIndex + 1, colIndex: colIndex})
-		}
-	}
-	return neighborPositions
-}
-
-func (ns *NumberSlot) HasSymbolAsNeighbor(schema *Schema) bool {
-	for _, pos := range ns.NeighborPositions(schema) {
-		if isNonEmptySymbol(schema.cells[pos.rowIndex][pos.colIndex]) {
-			return true
-		}
-	}
-	return false
-}
-
-func (ns *NumberSlot) IsInNeighborhood(schema *Schema, pos Pos) bool {
-	neighborPositions := ns.NeighborPositions(schema)
-	return slices.Contains(neighborPositions, pos)
-}
-
-func (ns *NumberSlot) Value(schema *Schema) int {
-	runes := schema.cells[ns.rowIndex][ns.colStartIndex:ns.colEndIndex]
-	value, _ := strconv.Atoi(string(runes))
-	return value
+func (ns *NumberSlot) Value() int {
+	return ns.value
 }
 
 type Gear struct {
-	pos   Pos
+	pos   grid.Pos
 	ratio int
 }
 
+// Symbol is a symbol of the schematic, with the numbers adjacent to it.
+type Symbol struct {
+	Pos     grid.Pos
+	Symbol  byte
+	Numbers []int
+}
+
+// Schema is an engine schematic. It indexes the number slot covering each cell, so that the numbers adjacent to a
+// cell are found in constant time.
 type Schema struct {
-	cells [][]rune
+	cells       *grid.Grid[byte]
+	numberSlots []NumberSlot
+	// slotIndex holds, for each cell, the index in numberSlots of the slot covering it, or noSlot.
+	slotIndex *grid.Grid[int]
 }
 
 func (s *Schema) ColCount() int {
-	return len(s.cells[0])
+	return s.cells.Width()
 }
 
 func (s *Schema) RowCount() int {
-	return len(s.cells)
+	return s.cells.Height()
 }
 
 func (s *Schema) NumberSlots() []NumberSlot {
-	var numberSlots []NumberSlot
-	for rowIndex, row := range s.cells {
+	return s.numberSlots
+}
+
+// indexNumberSlots finds the number slots of the schematic and fills the slot index.
+func (s *Schema) indexNumberSlots() {
+	s.slotIndex = grid.New[int](s.ColCount(), s.RowCount())
+	for rowIndex := range s.RowCount() {
+		row := s.cells.Row(rowIndex)
 		slot := NumberSlot{rowIndex: rowIndex, colStartIndex: -1, colEndIndex: -1}
 		for columnIndex, cell := range row {
+			s.slotIndex.Set(grid.Pos{X: columnIndex, Y: rowIndex}, noSlot)
 			if isDigit(cell) {
 				if slot.colStartIndex == -1 {
 					slot.colStartIndex = columnIndex
 				}
 				slot.colEndIndex = columnIndex + 1
+				slot.value = slot.value*10 + int(cell-'0')
+				s.slotIndex.Set(grid.Pos{X: columnIndex, Y: rowIndex}, len(s.numberSlots))
 			} else {
 				if slot.colStartIndex >= 0 {
-					numberSlots = append(numberSlots, slot)
+					s.numberSlots = append(s.numberSlots, slot)
 					slot = NumberSlot{rowIndex: rowIndex, colStartIndex: -1, colEndIndex: -1}
 				}
 			}
 		}
 		if slot.colStartIndex >= 0 {
-			slot.colEndIndex = len(row)
-			numberSlots = append(numberSlots, slot)
+			s.numberSlots = append(s.numberSlots, slot)
 		}
 	}
-	return numberSlots
 }
 
-func (s *Schema) PartNumbers() []int {
-	numberSlots := s.NumberSlots()
-	partNumbers := make([]int, 0)
-	for _, numberSlot := range numberSlots {
-		if numberSlot.HasSymbolAsNeighbor(s) {
-			numberPart := numberSlot.Value(s)
-			partNumbers = append(partNumbers, numberPart)
+// adjacentSlots returns the indexes of the number slots adjacent to the given position, each one once, in reading
+// order.
+func (s *Schema) adjacentSlots(pos grid.Pos) []int {
+	var slotIndexes []int
+	for neighbor := range s.cells.Neighbors8(pos) {
+		if slotIndex := s.slotIndex.At(neighbor); slotIndex != noSlot && !slices.Contains(slotIndexes, slotIndex) {
+			slotIndexes = append(slotIndexes, slotIndex)
+		}
+	}
+	slices.Sort(slotIndexes)
+	return slotIndexes
+}
+
+// AdjacentNumbers returns the numbers adjacent to the given position, in reading order.
+func (s *Schema) AdjacentNumbers(pos grid.Pos) []int {
+	slotIndexes := s.adjacentSlots(pos)
+	numbers := make([]int, len(slotIndexes))
+	for i, slotIndex := range slotIndexes {
+		numbers[i] = s.numberSlots[slotIndex].value
+	}
+	return numbers
+}
+
+// Symbols returns the symbols matching the given predicate, e.g. IsSymbol or Is('#'), with their adjacent numbers, in
+// reading order.
+func (s *Schema) Symbols(match func(cell byte) bool) []Symbol {
+	var symbols []Symbol
+	for pos, cell := range s.cells.All() {
+		if match(cell) {
+			symbols = append(symbols, Symbol{pos, cell, s.AdjacentNumbers(pos)})
 		}
 	}
+	return symbols
+}
+
+// isPartNumberSlot returns, for each number slot, whether it is adjacent to a symbol, i.e. whether it is a part number.
+func (s *Schema) isPartNumberSlot() []bool {
+	isPart := make([]bool, len(s.numberSlots))
+	for pos, cell := range s.cells.All() {
+		if IsSymbol(cell) {
+			for _, slotIndex := range s.adjacentSlots(pos) {
+				isPart[slotIndex] = true
+			}
+		}
+	}
+	return isPart
+}
+
+func (s *Schema) PartNumbers() []int {
+	partNumberSlots := s.PartNumberSlots()
+	partNumbers := make([]int, len(partNumberSlots))
+	for i, partNumberSlot := range partNumberSlots {
+		partNumbers[i] = partNumberSlot.value
+	}
 	return partNumbers
 }
 
 func (s *Schema) PartNumberSlots() []NumberSlot {
-	numberSlots := s.NumberSlots()
 	partNumbers := make([]NumberSlot, 0)
-	for _, numberSlot := range numberSlots {
-		if numberSlot.HasSymbolAsNeighbor(s) {
-			partNumbers = append(partNumbers, numberSlot)
+	for slotIndex, isPart := range s.isPartNumberSlot() {
+		if isPart {
+			partNumbers = append(partNumbers, s.numberSlots[slotIndex])
 		}
 	}
 	return partNumbers
 }
 
+// Gears returns the gears, i.e. the gear symbols adjacent to exactly two part numbers.
 func (s *Schema) Gears() []Gear {
-	possibleGearPositions := make([]Pos, 0)
-	for rowIndex, row := range s.cells {
-		for colIndex, cell := range row {
-			if cell == gearSymbol {
-				possibleGearPositions = append(possibleGearPositions, Pos{rowIndex, colIndex})
-			}
-		}
-	}
 	gears := make([]Gear, 0)
-	for _, possibleGearPos := range possibleGearPositions {
-		neighborPartNumbers := make([]NumberSlot, 0)
-		for _, partNumber := range s.PartNumberSlots() {
-			if partNumber.IsInNeighborhood(s, possibleGearPos) {
-				neighborPartNumbers = append(neighborPartNumbers, partNumber)
-			}
-		}
-		if len(neighborPartNumbers) == 2 {
-			gear := Gear{possibleGearPos, neighborPartNumbers[0].Value(s) * neighborPartNumbers[1].Value(s)}
-			gears = append(gears, gear)
+	for _, symbol := range s.Symbols(Is(gearSymbol)) {
+		if len(symbol.Numbers) == 2 {
+			gears = append(gears, Gear{symbol.Pos, symbol.Numbers[0] * symbol.Numbers[1]})
 		}
 	}
 	return gears
 }
 
-func isDigit(r rune) bool {
-	return slices.Contains(digits, r)
+// cellKind is the role of a cell of the schematic, as highlighted when rendering it.
+type cellKind byte
+
+// The kinds of cells, as marked below the rows in plain renderings.
+const (
+	emptyCell     cellKind = ' '
+	partNumber    cellKind = 'P'
+	nonPartNumber cellKind = '-'
+	gear          cellKind = 'G'
+	otherSymbol   cellKind = 'S'
+)
+
+var cellColors = map[cellKind]report.Color{
+	emptyCell:     report.Faint,
+	partNumber:    report.Green,
+	nonPartNumber: report.Red,
+	gear:          report.Yellow,
+	otherSymbol:   report.Blue,
 }
 
-func isNonEmptySymbol(r rune) bool {
-	return r != emptySymbol && !isDigit(r)
+// cellKinds returns the role of each cell of the schematic.
+func (s *Schema) cellKinds() *grid.Grid[cellKind] {
+	kinds := grid.New[cellKind](s.ColCount(), s.RowCount())
+	isPart := s.isPartNumberSlot()
+	for pos, cell := range s.cells.All() {
+		switch slotIndex := s.slotIndex.At(pos); {
+		case slotIndex != noSlot && isPart[slotIndex]:
+			kinds.Set(pos, partNumber)
+		case slotIndex != noSlot:
+			kinds.Set(pos, nonPartNumber)
+		case IsSymbol(cell):
+			kinds.Set(pos, otherSymbol)
+		default:
+			kinds.Set(pos, emptyCell)
+		}
+	}
+	for _, g := range s.Gears() {
+		kinds.Set(g.pos, gear)
+	}
+	return kinds
 }
 
-func newSchema(input string) *Schema {
-	lines := strings.Split(input, "\n")
-	cells := make([][]rune, len(lines[0]))
-	for i, row := range lines {
-		cells[i] = []rune(row)
+// Render renders the schematic, highlighting part numbers, numbers which are not part numbers, gears and other
+// symbols. Colored renderings use ANSI colors; plain ones mark the cells on a line below each row, e.g.:
+//
+//	467..114..
+//	PPP  ---
+//	...*......
+//	   G
+func (s *Schema) Render(colored bool) string {
+	kinds := s.cellKinds()
+	sb := strings.Builder{}
+	for rowIndex := range s.RowCount() {
+		row, rowKinds := s.cells.Row(rowIndex), kinds.Row(rowIndex)
+		if colored {
+			for i, cell := range row {
+				sb.WriteString(cellColors[rowKinds[i]].Paint(string(cell)))
+			}
+			sb.WriteByte('\n')
+			continue
+		}
+		sb.Write(row)
+		sb.WriteByte('\n')
+		marks := make([]byte, len(rowKinds))
+		for i, kind := range rowKinds {
+			marks[i] = byte(kind)
+		}
+		sb.WriteString(strings.TrimRight(string(marks), " ") + "\n")
 	}
-	return &Schema{cells}
+	return sb.String()
 }
 
-//go:embed input.txt
-var input string
+// String renders the schematic with plain markers.
+func (s *Schema) String() string {
+	return s.Render(false)
+}
 
-func main() {
-	schema := newSchema(input)
-	partNumbers := schema.PartNumbers()
-	fmt.Printf("Part numbers: %v\n\n", partNumbers)
+func isDigit(b byte) bool {
+	return slices.Contains(digits, b)
+}
 
-	partNumberSum := 0
-	for _, partNumber := range partNumbers {
-		partNumberSum += partNumber
-	}
-	fmt.Printf("Part number sum: %v\n\n", partNumberSum)
+// IsSymbol returns true if the given cell is a symbol, i.e. neither a digit nor empty.
+func IsSymbol(b byte) bool {
+	return b != emptySymbol && !isDigit(b)
+}
 
-	gears := schema.Gears()
-	fmt.Printf("Gears: %v\n\n", gears)
+// Is returns a predicate matching the given symbol.
+func Is(symbol byte) func(cell byte) bool {
+	return func(cell byte) bool {
+		return cell == symbol
+	}
+}
 
-	gearRatioSum := 0
-	for _, gear := range gears {
-		gearRatioSum += gear.ratio
+func newSchema(input string) (*Schema, error) {
+	cells, err := grid.ParseBytes(input)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Printf("Gear ratio sum: %v\n", gearRatioSum)
+	schema := &Schema{cells: cells}
+	schema.indexNumberSlots()
+	return schema, nil
+}
+
+func init() {
+	registry.Register(2023, 3, registry.Solver[*Schema]{
+		Parse: newSchema,
+		Part1: func(schema *Schema) (any, error) {
+			partNumbers := schema.PartNumbers()
+			slog.Debug("found part numbers", "partNumbers", partNumbers, "schema", schema)
+
+			partNumberSum := 0
+			for _, partNumber := range partNumbers {
+				partNumberSum += partNumber
+			}
+			return partNumberSum, nil
+		},
+		Part2: func(schema *Schema) (any, error) {
+			gears := schema.Gears()
+			slog.Debug("found gears", "gears", gears)
+
+			gearRatioSum := 0
+			for _, gear := range gears {
+				gearRatioSum += gear.ratio
+			}
+			return gearRatioSum, nil
+		},
+	})
 }
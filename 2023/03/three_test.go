@@ -0,0 +1,70 @@
+package day03
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/report"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 3)
+}
+
+func TestPartNumbers(t *testing.T) {
+	expected := []int{467, 35, 633, 617, 592, 755, 664, 598}
+	if actual := aoctest.ParseExample(t, newSchema).PartNumbers(); !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	schema := aoctest.ParseExample(t, newSchema)
+	symbols := schema.Symbols(Is('$'))
+	if len(symbols) != 1 || symbols[0].Pos != (grid.Pos{X: 3, Y: 8}) || !slices.Equal(symbols[0].Numbers, []int{664}) {
+		t.Errorf("expected $ at (3, 8) next to 664, got %v", symbols)
+	}
+	if symbols := schema.Symbols(IsSymbol); len(symbols) != 6 {
+		t.Errorf("expected 6 symbols, got %v", symbols)
+	}
+	if numbers := schema.AdjacentNumbers(grid.Pos{X: 3, Y: 1}); !slices.Equal(numbers, []int{467, 35}) {
+		t.Errorf("expected 467 and 35 next to the first gear, got %v", numbers)
+	}
+}
+
+func TestNumberTouchingSymbolTwice(t *testing.T) {
+	schema, err := newSchema("12.\n*#3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partNumbers := schema.PartNumbers(); !slices.Equal(partNumbers, []int{12, 3}) {
+		t.Errorf("expected each part number once, got %v", partNumbers)
+	}
+	if numbers := schema.AdjacentNumbers(grid.Pos{X: 1, Y: 1}); !slices.Equal(numbers, []int{12, 3}) {
+		t.Errorf("expected each adjacent number once, got %v", numbers)
+	}
+}
+
+func TestRender(t *testing.T) {
+	schema, err := newSchema("467..114..\n...*......\n..35..633.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "467..114..\nPPP  ---\n...*......\n   G\n..35..633.\n  PP  ---\n"
+	if actual := schema.Render(false); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	colored := schema.Render(true)
+	for _, expected := range []string{report.Green.Paint("4"), report.Red.Paint("1"), report.Yellow.Paint("*")} {
+		if !strings.Contains(colored, expected) {
+			t.Errorf("expected %q in colored rendering %q", expected, colored)
+		}
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 3)
+}
@@ -0,0 +1,326 @@
+package day05
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/super7ramp/aoc/parse"
+)
+
+type Associations []Association
+
+func associationsFrom(associationLines []parse.Token) (Associations, error) {
+	associations := make([]Association, len(associationLines))
+	for i, associationLine := range associationLines {
+		fields, err := associationLine.ExpectFields(3)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]int, len(fields))
+		for j, field := range fields {
+			if values[j], err = field.Int(); err != nil {
+				return nil, err
+			}
+		}
+		associations[i] = Association{values[0], values[1], values[2]}
+	}
+	return associations, nil
+}
+
+func (associations Associations) Destination(source int) int {
+	for _, association := range associations {
+		if dest := association.Destination(source); dest != -1 {
+			return dest
+		}
+	}
+	return source
+}
+
+// DestinationRanges returns the destination ranges corresponding to the given source ranges. Source ranges overlapping
+// several associations are split accordingly; the parts not covered by any association are mapped to themselves.
+func (associations Associations) DestinationRanges(sources []Range) []Range {
+	var destinations []Range
+	unmapped := slices.Clone(sources)
+	for _, association := range associations {
+		var stillUnmapped []Range
+		for _, source := range unmapped {
+			before, inside, after := source.splitBy(association.sourceRange())
+			if inside.length > 0 {
+				destinations = append(destinations, Range{association.Destination(inside.start), inside.length})
+			}
+			stillUnmapped = appendNonEmpty(stillUnmapped, before, after)
+		}
+		unmapped = stillUnmapped
+	}
+	return append(destinations, unmapped...)
+}
+
+// Sources returns the source values corresponding to the given destination value, sorted. There may be several of
+// them, or none, since associations may overlap the values mapped to themselves. Where associations overlap, only the
+// first one applies, as in Destination.
+func (associations Associations) Sources(destination int) []int {
+	associations = associations.Normalize()
+	var sources []int
+	for _, association := range associations {
+		if source := association.Source(destination); source != -1 {
+			sources = append(sources, source)
+		}
+	}
+	if !associations.covers(destination) {
+		sources = append(sources, destination)
+	}
+	slices.Sort(sources)
+	return slices.Compact(sources)
+}
+
+// SourceRanges returns the source ranges corresponding to the given destination ranges. They may overlap. Where
+// associations overlap, only the first one applies, as in DestinationRanges.
+func (associations Associations) SourceRanges(destinations []Range) []Range {
+	associations = associations.Normalize()
+	var sources []Range
+	for _, destination := range destinations {
+		for _, association := range associations {
+			if _, inside, _ := destination.splitBy(association.targetRange()); inside.length > 0 {
+				sources = append(sources, Range{association.Source(inside.start), inside.length})
+			}
+		}
+		// the values not covered by any association are mapped to themselves
+		uncovered := []Range{destination}
+		for _, association := range associations {
+			var stillUncovered []Range
+			for _, r := range uncovered {
+				before, _, after := r.splitBy(association.sourceRange())
+				stillUncovered = appendNonEmpty(stillUncovered, before, after)
+			}
+			uncovered = stillUncovered
+		}
+		sources = append(sources, uncovered...)
+	}
+	return sources
+}
+
+// covers returns true if the given source value belongs to the source range of an association.
+func (associations Associations) covers(source int) bool {
+	for _, association := range associations {
+		if association.Destination(source) != -1 {
+			return true
+		}
+	}
+	return false
+}
+
+type Association struct {
+	targetRangerStart int
+	sourceRangeStart  int
+	rangeLength       int
+}
+
+func (a *Association) Destination(source int) int {
+	if source < a.sourceRangeStart || source >= a.sourceRangeStart+a.rangeLength {
+		return -1
+	}
+	return a.targetRangerStart + source - a.sourceRangeStart
+}
+
+// Source returns the source value associated to the given destination value, or -1 if the destination is out of the
+// association's range.
+func (a *Association) Source(destination int) int {
+	if destination < a.targetRangerStart || destination >= a.targetRangerStart+a.rangeLength {
+		return -1
+	}
+	return a.sourceRangeStart + destination - a.targetRangerStart
+}
+
+func (a *Association) targetRange() Range {
+	return Range{a.targetRangerStart, a.rangeLength}
+}
+
+func (a *Association) sourceRange() Range {
+	return Range{a.sourceRangeStart, a.rangeLength}
+}
+
+type Range struct {
+	start  int
+	length int
+}
+
+func (r Range) end() int {
+	return r.start + r.length
+}
+
+// splitBy splits the range into its parts before, inside and after the given range. Some parts may be empty.
+func (r Range) splitBy(other Range) (before, inside, after Range) {
+	insideStart := min(max(r.start, other.start), r.end())
+	insideEnd := max(min(r.end(), other.end()), insideStart)
+	before = Range{r.start, insideStart - r.start}
+	inside = Range{insideStart, insideEnd - insideStart}
+	after = Range{insideEnd, r.end() - insideEnd}
+	return
+}
+
+func appendNonEmpty(ranges []Range, candidates ...Range) []Range {
+	for _, candidate := range candidates {
+		if candidate.length > 0 {
+			ranges = append(ranges, candidate)
+		}
+	}
+	return ranges
+}
+
+// ErrNotInvertible is returned when inverting associations which map several sources to the same destination.
+var ErrNotInvertible = errors.New("associations are not invertible")
+
+// offset returns the difference between the destination and the source of the association.
+func (a *Association) offset() int {
+	return a.targetRangerStart - a.sourceRangeStart
+}
+
+// Normalize returns equivalent associations, sorted by source, whose source ranges do not overlap. Where associations
+// overlap, the first one wins, as in Destination. Associations mapping values to themselves are dropped and adjacent
+// associations with the same offset are merged.
+func (associations Associations) Normalize() Associations {
+	var normalized Associations
+	var covered []Range
+	for _, association := range associations {
+		pieces := []Range{association.sourceRange()}
+		for _, c := range covered {
+			var uncovered []Range
+			for _, piece := range pieces {
+				before, _, after := piece.splitBy(c)
+				uncovered = appendNonEmpty(uncovered, before, after)
+			}
+			pieces = uncovered
+		}
+		for _, piece := range pieces {
+			normalized = append(normalized, Association{piece.start + association.offset(), piece.start, piece.length})
+		}
+		covered = appendNonEmpty(covered, association.sourceRange())
+	}
+	return normalized.sortAndMerge()
+}
+
+func (associations Associations) sortAndMerge() Associations {
+	associations = slices.DeleteFunc(associations, func(a Association) bool {
+		return a.rangeLength <= 0 || a.offset() == 0
+	})
+	slices.SortFunc(associations, func(a, b Association) int { return cmp.Compare(a.sourceRangeStart, b.sourceRangeStart) })
+	var merged Associations
+	for _, association := range associations {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.offset() == association.offset() && last.sourceRange().end() == association.sourceRangeStart {
+				last.rangeLength += association.rangeLength
+				continue
+			}
+		}
+		merged = append(merged, association)
+	}
+	return merged
+}
+
+// Then returns the associations equivalent to applying these associations, then the given ones. The result is
+// normalized.
+func (associations Associations) Then(next Associations) Associations {
+	first, second := associations.Normalize(), next.Normalize()
+	var composed Associations
+	for _, segment := range first.segments(first.span(second)) {
+		for _, nextSegment := range second.segments(segment.targetRange()) {
+			composed = append(composed, Association{nextSegment.targetRangerStart,
+				nextSegment.sourceRangeStart - segment.offset(), nextSegment.rangeLength})
+		}
+	}
+	return composed.sortAndMerge()
+}
+
+// Inverse returns the associations mapping the destinations back to their sources, or ErrNotInvertible if some
+// destinations have several sources. The result is normalized.
+func (associations Associations) Inverse() (Associations, error) {
+	normalized := associations.Normalize()
+	targets := make([]Range, len(normalized))
+	for i, association := range normalized {
+		targets[i] = association.targetRange()
+	}
+	slices.SortFunc(targets, func(a, b Range) int { return cmp.Compare(a.start, b.start) })
+	for i, target := range targets {
+		if i+1 < len(targets) && targets[i+1].start < target.end() {
+			return nil, ErrNotInvertible
+		}
+		// values out of the source ranges are mapped to themselves, so the targets must lie within the source ranges:
+		// normalized associations never map values to themselves, such segments are gaps
+		for _, segment := range normalized.segments(target) {
+			if segment.offset() == 0 {
+				return nil, ErrNotInvertible
+			}
+		}
+	}
+	inverse := make(Associations, len(normalized))
+	for i, association := range normalized {
+		inverse[i] = Association{association.sourceRangeStart, association.targetRangerStart, association.rangeLength}
+	}
+	return inverse.sortAndMerge(), nil
+}
+
+// span returns the smallest range containing the source and target ranges of these associations and of the given
+// ones. Out of it, both are the identity.
+func (associations Associations) span(other Associations) Range {
+	start, end := math.MaxInt, math.MinInt
+	for _, association := range slices.Concat(associations, other) {
+		for _, r := range []Range{association.sourceRange(), association.targetRange()} {
+			start, end = min(start, r.start), max(end, r.end())
+		}
+	}
+	if start > end {
+		return Range{}
+	}
+	return Range{start, end - start}
+}
+
+// segments returns the associations covering the given source range, cut to it, the gaps being filled with
+// associations mapping values to themselves. The associations must be normalized.
+func (associations Associations) segments(r Range) Associations {
+	var segments Associations
+	next := r.start
+	for _, association := range associations {
+		_, inside, _ := association.sourceRange().splitBy(r)
+		if inside.length <= 0 {
+			continue
+		}
+		if inside.start > next {
+			segments = append(segments, Association{next, next, inside.start - next})
+		}
+		segments = append(segments, Association{inside.start + association.offset(), inside.start, inside.length})
+		next = inside.end()
+	}
+	if next < r.end() {
+		segments = append(segments, Association{next, next, r.end() - next})
+	}
+	return segments
+}
+
+// String returns the breakpoints of the associations, one piece per line with the offset applied to its values, from
+// the lowest values to the highest ones, e.g.:
+//
+//	(-∞, 50): +0
+//	[50, 98): +2
+//	[98, 100): -48
+//	[100, +∞): +0
+func (associations Associations) String() string {
+	sb := strings.Builder{}
+	next := "-∞"
+	bracket := "("
+	for _, association := range associations.Normalize() {
+		if start := strconv.Itoa(association.sourceRangeStart); start != next {
+			fmt.Fprintf(&sb, "%s%s, %s): +0\n", bracket, next, start)
+		}
+		fmt.Fprintf(&sb, "[%d, %d): %+d\n", association.sourceRangeStart, association.sourceRange().end(),
+			association.offset())
+		next, bracket = strconv.Itoa(association.sourceRange().end()), "["
+	}
+	fmt.Fprintf(&sb, "%s%s, +∞): +0\n", bracket, next)
+	return sb.String()
+}
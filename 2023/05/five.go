@@ -1,133 +1,335 @@
-package main
+package day05
 
 import (
-	_ "embed"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
-	"strconv"
+	"slices"
 	"strings"
+	"sync"
+
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/search"
+)
+
+// Category is a category of things the almanac talks about, e.g. "seed" or "soil".
+type Category string
+
+const (
+	Seed     Category = "seed"
+	Location Category = "location"
 )
 
+// Map associates the values of its source category to the values of its destination category.
+type Map struct {
+	source       Category
+	destination  Category
+	associations Associations
+}
+
+// step is a map to follow, either forward (from source to destination) or backward.
+type step struct {
+	associations Associations
+	backward     bool
+}
+
+// Almanac is a list of seeds and of maps between categories. Maps form a graph between categories: values can be
+// converted from any category to any other one connected to it by a chain of maps, in whatever direction.
 type Almanac struct {
-	seeds                 []int
-	seedToSoil            Associations
-	soilToFertilizer      Associations
-	fertilizerToWater     Associations
-	waterToLight          Associations
-	lightToTemperature    Associations
-	temperatureToHumidity Associations
-	humidityToLocation    Associations
-}
-
-func AlmanachFrom(in string) *Almanac {
-	sections := strings.Split(in, "\n\n")
-	return &Almanac{
-		seeds:                 seedsFrom(sections[0]),
-		seedToSoil:            associationsFrom(sections[1]),
-		soilToFertilizer:      associationsFrom(sections[2]),
-		fertilizerToWater:     associationsFrom(sections[3]),
-		waterToLight:          associationsFrom(sections[4]),
-		lightToTemperature:    associationsFrom(sections[5]),
-		temperatureToHumidity: associationsFrom(sections[6]),
-		humidityToLocation:    associationsFrom(sections[7]),
-	}
-}
-
-func seedsFrom(seedSection string) []int {
-	seedFields := strings.Fields(seedSection)[1:]
-	seeds := make([]int, len(seedFields))
-	for i, seedField := range seedFields {
-		seeds[i], _ = strconv.Atoi(seedField)
-	}
-	return seeds
-}
-
-func (a *Almanac) LocationForSeed(seed int) int {
-	soil := a.seedToSoil.Destination(seed)
-	fertilizer := a.soilToFertilizer.Destination(soil)
-	water := a.fertilizerToWater.Destination(fertilizer)
-	light := a.waterToLight.Destination(water)
-	temperature := a.lightToTemperature.Destination(light)
-	humidity := a.temperatureToHumidity.Destination(temperature)
-	return a.humidityToLocation.Destination(humidity)
-}
-
-func (a *Almanac) SeedRanges() []Range {
-	seedRanges := make([]Range, len(a.seeds)/2)
-	for i := 0; i < len(a.seeds); i += 2 {
-		seedRanges[i/2] = Range{a.seeds[i], a.seeds[i+1]}
+	seeds []int
+	// seedLine is the line listing the seeds, to locate errors about them.
+	seedLine parse.Token
+	maps     []Map
+	// seedToLocation composes the chain of maps from seeds to locations, which the puzzle is about, on first call.
+	seedToLocation func() (Associations, error)
+}
+
+func AlmanachFrom(in string) (*Almanac, error) {
+	sections := parse.Blocks(in)
+	if len(sections) == 0 {
+		return nil, errors.New("empty almanac")
+	}
+	seeds, err := seedsFrom(sections[0])
+	if err != nil {
+		return nil, err
+	}
+	almanac := &Almanac{seeds: seeds, seedLine: sections[0][0]}
+	for _, section := range sections[1:] {
+		m, err := mapFrom(section)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := almanac.stepBetween(m.source, m.destination); exists {
+			return nil, section[0].Errorf("duplicate map between %s and %s", m.source, m.destination)
+		}
+		almanac.maps = append(almanac.maps, m)
 	}
-	return seedRanges
+	almanac.seedToLocation = sync.OnceValues(almanac.composeSeedToLocation)
+	return almanac, nil
 }
 
-type Associations []Association
+func seedsFrom(seedSection []parse.Token) ([]int, error) {
+	if len(seedSection) != 1 {
+		return nil, seedSection[1].Errorf("expected a single line of seeds")
+	}
+	seedFields, err := seedSection[0].TrimPrefix("seeds:")
+	if err != nil {
+		return nil, err
+	}
+	return seedFields.Ints()
+}
 
-func associationsFrom(mapSection string) Associations {
-	associationLines := strings.Split(mapSection, "\n")[1:]
-	associations := make([]Association, len(associationLines))
-	for i, associationLine := range associationLines {
-		fields := strings.Fields(associationLine)
-		targetRangerStart, _ := strconv.Atoi(fields[0])
-		sourceRangeStart, _ := strconv.Atoi(fields[1])
-		rangeLength, _ := strconv.Atoi(fields[2])
-		associations[i] = Association{targetRangerStart, sourceRangeStart, rangeLength}
+func mapFrom(mapSection []parse.Token) (Map, error) {
+	header := mapSection[0]
+	name, found := strings.CutSuffix(header.Text, " map:")
+	source, destination, separated := strings.Cut(name, "-to-")
+	if !found || !separated || source == "" || destination == "" {
+		return Map{}, header.Errorf("expected a map header, e.g. \"seed-to-soil map:\"")
+	}
+	associations, err := associationsFrom(mapSection[1:])
+	if err != nil {
+		return Map{}, err
 	}
-	return associations
+	return Map{Category(source), Category(destination), associations}, nil
 }
 
-func (associations Associations) Destination(source int) int {
-	for _, association := range associations {
-		if dest := association.Destination(source); dest != -1 {
-			return dest
+// Categories returns the categories of the almanac, in order of appearance.
+func (a *Almanac) Categories() []Category {
+	var categories []Category
+	for _, m := range a.maps {
+		for _, category := range []Category{m.source, m.destination} {
+			if !slices.Contains(categories, category) {
+				categories = append(categories, category)
+			}
 		}
 	}
-	return source
+	return categories
 }
 
-type Association struct {
-	targetRangerStart int
-	sourceRangeStart  int
-	rangeLength       int
+// Convert returns the values of the category to corresponding to the given value of the category from. Following maps
+// forward gives a single value, but following them backward may give several values, or none: they are sorted. It
+// returns an error if the categories are not connected by a chain of maps.
+func (a *Almanac) Convert(value int, from, to Category) ([]int, error) {
+	steps, err := a.path(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return convert([]int{value}, steps), nil
 }
 
-func (a *Association) Destination(source int) int {
-	if source < a.sourceRangeStart || source >= a.sourceRangeStart+a.rangeLength {
-		return -1
+// ConvertRanges is like Convert, for ranges of values. The returned ranges may overlap when following maps backward.
+func (a *Almanac) ConvertRanges(ranges []Range, from, to Category) ([]Range, error) {
+	steps, err := a.path(from, to)
+	if err != nil {
+		return nil, err
 	}
-	return a.targetRangerStart + source - a.sourceRangeStart
+	return convertRanges(ranges, steps), nil
 }
 
-type Range struct {
-	start  int
-	length int
+func convert(values []int, steps []step) []int {
+	for _, step := range steps {
+		var next []int
+		for _, value := range values {
+			if step.backward {
+				next = append(next, step.associations.Sources(value)...)
+			} else {
+				next = append(next, step.associations.Destination(value))
+			}
+		}
+		slices.Sort(next)
+		values = slices.Compact(next)
+	}
+	return values
 }
 
-func (r *Range) end() int {
-	return r.start + r.length
+func convertRanges(ranges []Range, steps []step) []Range {
+	for _, step := range steps {
+		if step.backward {
+			ranges = step.associations.SourceRanges(ranges)
+		} else {
+			ranges = step.associations.DestinationRanges(ranges)
+		}
+	}
+	return ranges
 }
 
-//go:embed input.txt
-var input string
+// compose returns the associations equivalent to the given chain of maps.
+func compose(steps []step) (Associations, error) {
+	var composed Associations
+	for _, step := range steps {
+		associations := step.associations
+		if step.backward {
+			var err error
+			if associations, err = associations.Inverse(); err != nil {
+				return nil, err
+			}
+		}
+		composed = composed.Then(associations)
+	}
+	return composed, nil
+}
 
-func main() {
-	almanac := AlmanachFrom(input)
+// path returns the shortest chain of maps between the given categories.
+func (a *Almanac) path(from, to Category) ([]step, error) {
+	categories := a.Categories()
+	for _, category := range []Category{from, to} {
+		if !slices.Contains(categories, category) {
+			return nil, fmt.Errorf("unknown category %q", category)
+		}
+	}
+	isGoal := func(category Category) bool { return category == to }
+	path, found := search.BFS(from, isGoal, a.neighbors)
+	if !found {
+		return nil, fmt.Errorf("no chain of maps between %s and %s", from, to)
+	}
+	steps := make([]step, 0, len(path.States)-1)
+	for i := 1; i < len(path.States); i++ {
+		step, _ := a.stepBetween(path.States[i-1], path.States[i])
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
 
-	minLocation := math.MaxInt
-	for _, seed := range almanac.seeds {
-		if location := almanac.LocationForSeed(seed); location < minLocation {
-			minLocation = location
+func (a *Almanac) neighbors(category Category) []Category {
+	var neighbors []Category
+	for _, m := range a.maps {
+		if m.source == category {
+			neighbors = append(neighbors, m.destination)
+		} else if m.destination == category {
+			neighbors = append(neighbors, m.source)
 		}
 	}
-	fmt.Println("Min location for seeds (part 1): ", minLocation)
+	return neighbors
+}
 
-	// the following takes 2 min on a M2, there must be a cleverer way 😅
-	minLocation = math.MaxInt
-	for _, seedRange := range almanac.SeedRanges() {
+// stepBetween returns the step going from one category to the other, if they are directly connected by a map.
+func (a *Almanac) stepBetween(from, to Category) (step, bool) {
+	for _, m := range a.maps {
+		if m.source == from && m.destination == to {
+			return step{m.associations, false}, true
+		}
+		if m.source == to && m.destination == from {
+			return step{m.associations, true}, true
+		}
+	}
+	return step{}, false
+}
+
+// SeedToLocation returns the single map from seeds to locations, composed from the chain of maps between them. It
+// returns an error if there is no such chain, or if it follows a map backward which cannot be inverted.
+func (a *Almanac) SeedToLocation() (Associations, error) {
+	return a.seedToLocation()
+}
+
+func (a *Almanac) composeSeedToLocation() (Associations, error) {
+	steps, err := a.path(Seed, Location)
+	if err != nil {
+		return nil, err
+	}
+	seedToLocation, err := compose(steps)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compose the maps from %s to %s: %w", Seed, Location, err)
+	}
+	return seedToLocation, nil
+}
+
+// LocationForSeed returns the location corresponding to the given seed.
+func (a *Almanac) LocationForSeed(seed int) (int, error) {
+	seedToLocation, err := a.SeedToLocation()
+	if err != nil {
+		return 0, err
+	}
+	return seedToLocation.Destination(seed), nil
+}
+
+// LocationRangesForSeedRanges returns the location ranges corresponding to the given seed ranges. The seed ranges are
+// mapped as a whole rather than seed by seed, being split wherever they straddle an association boundary.
+func (a *Almanac) LocationRangesForSeedRanges(seedRanges []Range) ([]Range, error) {
+	seedToLocation, err := a.SeedToLocation()
+	if err != nil {
+		return nil, err
+	}
+	return seedToLocation.DestinationRanges(seedRanges), nil
+}
+
+// MinLocationForSeedRanges returns the lowest location corresponding to any seed of the given ranges, or math.MaxInt
+// if the ranges are empty.
+func (a *Almanac) MinLocationForSeedRanges(seedRanges []Range) (int, error) {
+	locationRanges, err := a.LocationRangesForSeedRanges(seedRanges)
+	if err != nil {
+		return 0, err
+	}
+	minLocation := math.MaxInt
+	for _, locationRange := range locationRanges {
+		minLocation = min(minLocation, locationRange.start)
+	}
+	return minLocation, nil
+}
+
+// minLocationForSeedRangesBruteForce is like MinLocationForSeedRanges, but maps the seeds one by one through the chain
+// of maps. It is meant to check the former on small inputs, as it takes minutes on the real input.
+func (a *Almanac) minLocationForSeedRangesBruteForce(seedRanges []Range) (int, error) {
+	steps, err := a.path(Seed, Location)
+	if err != nil {
+		return 0, err
+	}
+	minLocation := math.MaxInt
+	for _, seedRange := range seedRanges {
 		for seed := seedRange.start; seed < seedRange.end(); seed++ {
-			if location := almanac.LocationForSeed(seed); location < minLocation {
-				minLocation = location
-			}
+			minLocation = min(minLocation, slices.Min(convert([]int{seed}, steps)))
 		}
 	}
-	fmt.Println("Min location for seeds (part 2): ", minLocation)
+	return minLocation, nil
+}
+
+// MinLocationForSeeds returns the lowest location corresponding to any of the seeds, or math.MaxInt if there is none.
+func (a *Almanac) MinLocationForSeeds() (int, error) {
+	seedToLocation, err := a.SeedToLocation()
+	if err != nil {
+		return 0, err
+	}
+	minLocation := math.MaxInt
+	for _, seed := range a.seeds {
+		minLocation = min(minLocation, seedToLocation.Destination(seed))
+	}
+	return minLocation, nil
+}
+
+// SeedRanges reads the seeds as pairs of range start and length. It returns an error if the number of seeds is odd.
+func (a *Almanac) SeedRanges() ([]Range, error) {
+	if len(a.seeds)%2 != 0 {
+		return nil, a.seedLine.Errorf("expected pairs of seed range start and length, got %d numbers", len(a.seeds))
+	}
+	seedRanges := make([]Range, len(a.seeds)/2)
+	for i := 0; i < len(a.seeds); i += 2 {
+		seedRanges[i/2] = Range{a.seeds[i], a.seeds[i+1]}
+	}
+	return seedRanges, nil
+}
+
+func init() {
+	registry.Register(2023, 5, registry.Solver[*Almanac]{
+		Parse: AlmanachFrom,
+		Part1: func(almanac *Almanac) (any, error) {
+			return almanac.MinLocationForSeeds()
+		},
+		Part2: func(almanac *Almanac) (any, error) {
+			seedToLocation, err := almanac.SeedToLocation()
+			if err != nil {
+				return nil, err
+			}
+			slog.Debug("composed seed-to-location map", "breakpoints", seedToLocation)
+			seedRanges, err := almanac.SeedRanges()
+			if err != nil {
+				return nil, err
+			}
+			minLocation, err := almanac.MinLocationForSeedRanges(seedRanges)
+			if err != nil {
+				return nil, err
+			}
+			return minLocation, nil
+		},
+	})
 }
@@ -0,0 +1,216 @@
+package day05
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/parse"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 5)
+}
+
+func TestMinLocationForSeedRanges(t *testing.T) {
+	almanac := aoctest.ParseExample(t, AlmanachFrom)
+	exampleSeedRanges, err := almanac.SeedRanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]Range{
+		"example seed ranges": exampleSeedRanges,
+		"single seeds":        {{14, 1}, {55, 1}, {79, 1}},
+		"straddling ranges":   {{0, 100}},
+		"disjoint ranges":     {{0, 10}, {45, 20}, {90, 5}},
+	}
+	for name, seedRanges := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected, err := almanac.minLocationForSeedRangesBruteForce(seedRanges)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual, err := almanac.MinLocationForSeedRanges(seedRanges); err != nil || actual != expected {
+				t.Errorf("expected %d, got %d", expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	almanac := aoctest.ParseExample(t, AlmanachFrom)
+	tests := []struct {
+		value    int
+		from, to Category
+		expected []int
+	}{
+		{79, "seed", "water", []int{81}},
+		{79, "seed", "location", []int{82}},
+		{82, "location", "seed", []int{79}},
+		{74, "light", "soil", []int{81}},
+	}
+	for _, test := range tests {
+		actual, err := almanac.Convert(test.value, test.from, test.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("%s %d to %s: expected %v, got %v", test.from, test.value, test.to, test.expected, actual)
+		}
+	}
+	if _, err := almanac.Convert(79, "seed", "unicorn"); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+}
+
+func TestConvertBackAndForth(t *testing.T) {
+	almanac := aoctest.ParseExample(t, AlmanachFrom)
+	for seed := range 100 {
+		location, err := almanac.LocationForSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seeds, err := almanac.Convert(location, Location, Seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(seeds, seed) {
+			t.Errorf("expected location %d to map back to seed %d, got %v", location, seed, seeds)
+		}
+	}
+}
+
+func TestThen(t *testing.T) {
+	steps, err := aoctest.ParseExample(t, AlmanachFrom).path(Seed, Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var composed Associations
+	for _, step := range steps {
+		composed = composed.Then(step.associations)
+	}
+	for seed := range 120 {
+		expected := slices.Min(convert([]int{seed}, steps))
+		if actual := composed.Destination(seed); actual != expected {
+			t.Errorf("seed %d: expected location %d, got %d", seed, expected, actual)
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	seedToLocation, err := aoctest.ParseExample(t, AlmanachFrom).SeedToLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inverse, err := seedToLocation.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := range 120 {
+		if actual := inverse.Destination(seedToLocation.Destination(seed)); actual != seed {
+			t.Errorf("expected seed %d, got %d", seed, actual)
+		}
+	}
+	overlapping := Associations{{10, 0, 5}, {12, 20, 5}}
+	if _, err := overlapping.Inverse(); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("expected ErrNotInvertible for overlapping targets, got %v", err)
+	}
+	outOfSources := Associations{{10, 0, 5}}
+	if _, err := outOfSources.Inverse(); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("expected ErrNotInvertible for targets out of the sources, got %v", err)
+	}
+}
+
+func TestSourcesOfOverlappingAssociations(t *testing.T) {
+	// sources 3 and 4 are mapped by the first association, which shadows the second one
+	overlapping := Associations{{10, 0, 5}, {20, 3, 5}}
+	if actual := overlapping.Sources(20); !slices.Equal(actual, []int{20}) {
+		t.Errorf("expected [20], got %v", actual)
+	}
+	for destination := range 30 {
+		for _, source := range overlapping.Sources(destination) {
+			if actual := overlapping.Destination(source); actual != destination {
+				t.Errorf("source %d of %d maps to %d", source, destination, actual)
+			}
+		}
+	}
+	for _, sourceRange := range overlapping.SourceRanges([]Range{{0, 30}}) {
+		for _, destinationRange := range overlapping.DestinationRanges([]Range{sourceRange}) {
+			if destinationRange.start < 0 || destinationRange.end() > 30 {
+				t.Errorf("source range %v maps to %v, out of the destination range", sourceRange, destinationRange)
+			}
+		}
+	}
+	if actual := overlapping.SourceRanges([]Range{{20, 2}}); !slices.Equal(actual, []Range{{20, 2}}) {
+		t.Errorf("expected [{20 2}], got %v", actual)
+	}
+}
+
+func TestAssociationsString(t *testing.T) {
+	associations := Associations{{50, 98, 2}, {52, 50, 48}, {60, 60, 5}}
+	expected := "(-∞, 50): +0\n[50, 98): +2\n[98, 100): -48\n[100, +∞): +0\n"
+	if actual := associations.String(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestReorderedMaps(t *testing.T) {
+	reordered := aoctest.ParseExample(t, func(in string) (*Almanac, error) {
+		sections := strings.Split(strings.TrimSpace(in), "\n\n")
+		slices.Reverse(sections[1:])
+		return AlmanachFrom(strings.Join(sections, "\n\n"))
+	})
+	seedRanges, err := reordered.SeedRanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, err := reordered.MinLocationForSeedRanges(seedRanges); err != nil || location != 46 {
+		t.Errorf("expected 46, got %d, %v", location, err)
+	}
+}
+
+func TestAlmanacWithoutSeedToLocation(t *testing.T) {
+	tests := map[string]struct {
+		in       string
+		expected error
+	}{
+		"no location": {"seeds: 1 2\n\nseed-to-soil map:\n50 0 5\n\nwater-to-soil map:\n10 0 60", nil},
+		"not invertible": {
+			"seeds: 1 2\n\nseed-to-soil map:\n50 0 5\n\nlocation-to-soil map:\n10 0 5\n12 20 5", ErrNotInvertible},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			almanac, err := AlmanachFrom(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if soils, err := almanac.Convert(2, Seed, "soil"); err != nil || !slices.Equal(soils, []int{52}) {
+				t.Errorf("expected seed 2 to convert to soil [52], got %v, %v", soils, err)
+			}
+			if _, err := almanac.SeedToLocation(); err == nil || test.expected != nil && !errors.Is(err, test.expected) {
+				t.Errorf("expected an error composing the seed-to-location map, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOddSeedCount(t *testing.T) {
+	almanac, err := AlmanachFrom("seeds: 79 14 55\n\nseed-to-location map:\n50 98 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, err := almanac.MinLocationForSeeds(); err != nil || location != 14 {
+		t.Errorf("expected part 1 to find location 14, got %v, %v", location, err)
+	}
+	_, err = almanac.SeedRanges()
+	var parseErr *parse.Error
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 {
+		t.Errorf("expected a parse error on line 1, got %v", err)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 5)
+}
@@ -1,53 +1,184 @@
-package main
+package day06
 
 import (
-	_ "embed"
 	"fmt"
-	"strconv"
+	"log/slog"
+	"math/big"
 	"strings"
+
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
-//go:embed input.txt
-var input string
+// Race is a boat race: its duration and the record distance to beat, in milliseconds and millimeters.
+type Race struct {
+	time     *big.Int
+	distance *big.Int
+}
+
+func (r Race) String() string {
+	return fmt.Sprintf("%v ms to beat %v mm", r.time, r.distance)
+}
+
+// Reading is the way the sheet of paper listing the races is read.
+type Reading int
+
+const (
+	// Spaced reads each number of the sheet as a race.
+	Spaced Reading = iota
+	// Kerned reads the sheet as a single race, ignoring the spaces between the digits of each line.
+	Kerned
+)
+
+// Sheet is the sheet of paper listing the races, read both ways.
+type Sheet struct {
+	races      []Race
+	kernedRace Race
+}
 
-func main() {
-	lines := strings.Split(input, "\n")
-	times := parseLine(lines[0])
-	distances := parseLine(lines[1])
-	fmt.Println("Times: ", times)
-	fmt.Println("Distances: ", distances)
+func init() {
+	registry.Register(2023, 6, registry.Solver[Sheet]{
+		Parse: parseSheet,
+		Part1: func(sheet Sheet) (any, error) {
+			slog.Debug("parsed races", "races", sheet.races)
+			return computeNumberOfWays(sheet.races), nil
+		},
+		Part2: func(sheet Sheet) (any, error) {
+			slog.Debug("parsed kerned race", "race", sheet.kernedRace)
+			return computeNumberOfWays([]Race{sheet.kernedRace}), nil
+		},
+	})
+}
 
-	numberOfWays := computeNumberOfWays(times, distances)
-	fmt.Println("Number of ways to beat the record (part 1): ", numberOfWays)
+func parseSheet(input string) (Sheet, error) {
+	races, err := ParseRaces(input, Spaced)
+	if err != nil {
+		return Sheet{}, err
+	}
+	kernedRaces, err := ParseRaces(input, Kerned)
+	if err != nil {
+		return Sheet{}, err
+	}
+	return Sheet{races, kernedRaces[0]}, nil
+}
 
-	numberOfWaysPart2 := computeNumberOfWays([]int64{58819676}, []int64{434104122191218})
-	fmt.Println("Number of ways to beat the record (part 2): ", numberOfWaysPart2)
+// ParseRaces parses the races of the given sheet, read the given way.
+func ParseRaces(input string, reading Reading) ([]Race, error) {
+	lines, err := parse.ExpectLines(input, 2)
+	if err != nil {
+		return nil, err
+	}
+	times, err := parseLine(lines[0], "Time:", reading)
+	if err != nil {
+		return nil, err
+	}
+	distances, err := parseLine(lines[1], "Distance:", reading)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) != len(distances) {
+		return nil, lines[1].Errorf("expected %d distances, got %d", len(times), len(distances))
+	}
+	races := make([]Race, len(times))
+	for i := range races {
+		races[i] = Race{times[i], distances[i]}
+	}
+	return races, nil
 }
 
-func parseLine(line string) []int64 {
-	fields := strings.Fields(line)[1:]
-	numbers := make([]int64, len(fields))
+func parseLine(line parse.Token, header string, reading Reading) ([]*big.Int, error) {
+	fieldsToken, err := line.TrimPrefix(header)
+	if err != nil {
+		return nil, err
+	}
+	fields := fieldsToken.Fields()
+	if len(fields) == 0 {
+		return nil, fieldsToken.Errorf("expected numbers")
+	}
+	if reading == Kerned {
+		digits := make([]string, len(fields))
+		for i, field := range fields {
+			digits[i] = field.Text
+		}
+		fields = []parse.Token{{Text: strings.Join(digits, ""), Line: fields[0].Line, Column: fields[0].Column}}
+	}
+	numbers := make([]*big.Int, len(fields))
 	for i, field := range fields {
-		numbers[i], _ = strconv.ParseInt(field, 10, 64)
+		number, ok := new(big.Int).SetString(field.Text, 10)
+		if !ok || number.Sign() < 0 {
+			return nil, field.Errorf("invalid number %q", field.Text)
+		}
+		numbers[i] = number
 	}
-	return numbers
+	return numbers, nil
 }
 
-func computeNumberOfWays(times []int64, distances []int64) interface{} {
-	product := int64(1)
-	for i := 0; i < len(times); i++ {
-		product *= countSolutions(times[i], distances[i])
+func computeNumberOfWays(races []Race) *big.Int {
+	product := big.NewInt(1)
+	for _, race := range races {
+		product.Mul(product, race.countSolutions())
 	}
 	return product
 }
 
-func countSolutions(raceTimeInMs, minimumDistanceInMs int64) int64 {
-	solutionCount := int64(0)
-	for holdDurationInMs := int64(1); holdDurationInMs < raceTimeInMs; holdDurationInMs++ {
-		distanceCoveredInMm := (raceTimeInMs - holdDurationInMs) * holdDurationInMs
-		if distanceCoveredInMm > minimumDistanceInMs {
-			solutionCount++
+// countSolutions returns the number of hold durations beating the record distance. Holding the button for h ms covers
+// h * (time - h) mm, so the winning durations lie strictly between the roots of h² - time*h + distance, which are
+// symmetric around time/2. It computes with int64 when possible, with math/big otherwise.
+func (r Race) countSolutions() *big.Int {
+	if r.time.IsInt64() && r.distance.IsInt64() {
+		if count, err := countSolutions(r.time.Int64(), r.distance.Int64()); err == nil {
+			return big.NewInt(count)
 		}
 	}
-	return solutionCount
+	return bigCountSolutions(r.time, r.distance)
+}
+
+func countSolutions(raceTimeInMs, minimumDistanceInMm int64) (int64, error) {
+	timeSquared, err := aocmath.Mul(raceTimeInMs, raceTimeInMs)
+	if err != nil {
+		return 0, err
+	}
+	fourDistances, err := aocmath.Mul(4, minimumDistanceInMm)
+	if err != nil {
+		return 0, err
+	}
+	discriminant := timeSquared - fourDistances
+	if discriminant < 0 {
+		return 0, nil
+	}
+	// the first winning duration is right above the lower root, (time - √discriminant) / 2
+	holdDurationInMs := (raceTimeInMs - aocmath.ISqrt(discriminant)) / 2
+	for holdDurationInMs <= raceTimeInMs/2 && holdDurationInMs*(raceTimeInMs-holdDurationInMs) <= minimumDistanceInMm {
+		holdDurationInMs++
+	}
+	return max(raceTimeInMs-2*holdDurationInMs+1, 0), nil
+}
+
+// bigCountSolutions is countSolutions with arbitrary precision.
+func bigCountSolutions(raceTimeInMs, minimumDistanceInMm *big.Int) *big.Int {
+	discriminant := new(big.Int).Mul(raceTimeInMs, raceTimeInMs)
+	discriminant.Sub(discriminant, new(big.Int).Lsh(minimumDistanceInMm, 2))
+	if discriminant.Sign() < 0 {
+		return new(big.Int)
+	}
+	halfTime := new(big.Int).Rsh(raceTimeInMs, 1)
+	holdDurationInMs := new(big.Int).Sub(raceTimeInMs, discriminant.Sqrt(discriminant))
+	holdDurationInMs.Rsh(holdDurationInMs, 1)
+	distanceCoveredInMm := new(big.Int)
+	for holdDurationInMs.Cmp(halfTime) <= 0 {
+		distanceCoveredInMm.Sub(raceTimeInMs, holdDurationInMs)
+		distanceCoveredInMm.Mul(distanceCoveredInMm, holdDurationInMs)
+		if distanceCoveredInMm.Cmp(minimumDistanceInMm) > 0 {
+			break
+		}
+		holdDurationInMs.Add(holdDurationInMs, big.NewInt(1))
+	}
+	count := new(big.Int).Lsh(holdDurationInMs, 1)
+	count.Sub(raceTimeInMs, count)
+	count.Add(count, big.NewInt(1))
+	if count.Sign() < 0 {
+		return new(big.Int)
+	}
+	return count
 }
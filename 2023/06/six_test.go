@@ -0,0 +1,63 @@
+package day06
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 6)
+}
+
+func TestCountSolutions(t *testing.T) {
+	for raceTime := range int64(40) {
+		for distance := range raceTime * raceTime / 4 {
+			expected := int64(0)
+			for holdDuration := range raceTime {
+				if holdDuration*(raceTime-holdDuration) > distance {
+					expected++
+				}
+			}
+			actual, err := countSolutions(raceTime, distance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bigActual := bigCountSolutions(big.NewInt(raceTime), big.NewInt(distance))
+			if actual != expected || bigActual.Int64() != expected {
+				t.Errorf("time %d, distance %d: expected %d, got %d and %v", raceTime, distance, expected, actual,
+					bigActual)
+			}
+		}
+	}
+}
+
+func TestCountSolutionsBeyondInt64(t *testing.T) {
+	races, err := ParseRaces("Time: 4000000000 0000000000\nDistance: 1 0\n", Kerned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// all durations but 0 and the race time win
+	expected := "39999999999999999999"
+	if actual := computeNumberOfWays(races).String(); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestParseRaces(t *testing.T) {
+	if _, err := ParseRaces("Time: 7 15\nDistance: 9 -40\n", Spaced); err == nil {
+		t.Error("expected an error for a negative distance")
+	}
+	races, err := ParseRaces("Time: 7 15\nDistance: 9 40\n", Kerned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(races) != 1 || races[0].String() != "715 ms to beat 940 mm" {
+		t.Errorf("expected a single 715 ms race, got %v", races)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 6)
+}
@@ -1,252 +1,303 @@
-package main
+package day07
 
 import (
-	_ "embed"
+	"cmp"
 	"fmt"
+	"log/slog"
+	"maps"
 	"slices"
-	"sort"
-	"strconv"
 	"strings"
-)
 
-const (
-	joker = iota
-	two   = iota + 1
-	three
-	four
-	five
-	six
-	seven
-	height
-	nine
-	ten
-	jack
-	queen
-	king
-	ace
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
-const (
-	highCard = iota
-	onePair
-	twoPair
-	threeOfAKind
-	fullHouse
-	fourOfAKind
-	fiveOfAKind
+// Card is the label of a card, e.g. 'A' or '2'.
+type Card byte
+
+// Deck lists the labels of the cards.
+const Deck = "23456789TJQKA"
+
+// Rules defines how cards rank: their order, from the weakest to the strongest, and the wildcards, which act like
+// whatever card would make the hand the strongest when determining its type.
+type Rules struct {
+	name      string
+	order     []Card
+	strengths map[Card]int
+	wildcards []Card
+}
+
+var (
+	// ClassicRules are the rules of part 1: jacks rank between tens and queens.
+	ClassicRules = mustRules("classic", "23456789TJQKA", "")
+	// JokerRules are the rules of part 2: jokers are wildcards, and the weakest cards.
+	JokerRules = mustRules("joker", "J23456789TQKA", "J")
 )
 
-type Card int
-
-func CardFrom(r rune) Card {
-	switch r {
-	case '2':
-		return two
-	case '3':
-		return three
-	case '4':
-		return four
-	case '5':
-		return five
-	case '6':
-		return six
-	case '7':
-		return seven
-	case '8':
-		return height
-	case '9':
-		return nine
-	case 'T':
-		return ten
-	case 'J':
-		//return jack // part 1
-		return joker // part 2
-	case 'Q':
-		return queen
-	case 'K':
-		return king
-	case 'A':
-		return ace
-	}
-	return -1
-}
-
-type Hand [5]Card
+// NewRules returns the rules ordering the cards of the deck as given, from the weakest to the strongest, the given
+// cards being wildcards. The order must list each card of the Deck once.
+func NewRules(name, order, wildcards string) (Rules, error) {
+	rules := Rules{name: name, strengths: make(map[Card]int, len(order))}
+	for i, label := range []byte(order) {
+		if !strings.ContainsRune(Deck, rune(label)) {
+			return Rules{}, fmt.Errorf("rules %s: unknown card %q", name, label)
+		}
+		if _, duplicate := rules.strengths[Card(label)]; duplicate {
+			return Rules{}, fmt.Errorf("rules %s: card %q ranked twice", name, label)
+		}
+		rules.strengths[Card(label)] = i
+		rules.order = append(rules.order, Card(label))
+	}
+	if len(rules.strengths) != len(Deck) {
+		return Rules{}, fmt.Errorf("rules %s: expected %d cards to be ranked, got %d", name, len(Deck), len(order))
+	}
+	for _, label := range []byte(wildcards) {
+		if _, known := rules.strengths[Card(label)]; !known {
+			return Rules{}, fmt.Errorf("rules %s: unknown wildcard %q", name, label)
+		}
+		rules.wildcards = append(rules.wildcards, Card(label))
+	}
+	return rules, nil
+}
+
+func mustRules(name, order, wildcards string) Rules {
+	rules, err := NewRules(name, order, wildcards)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+func (r Rules) String() string {
+	return r.name
+}
+
+func (r Rules) isWildcard(card Card) bool {
+	return slices.Contains(r.wildcards, card)
+}
+
+// Hand is a hand of cards, of any size.
+type Hand []Card
 
 type HandWithBid struct {
 	Hand
 	bid int
 }
 
-func HandFrom(s string) Hand {
-	var h Hand
-	for i, r := range s {
-		h[i] = CardFrom(r)
+func (h HandWithBid) String() string {
+	return fmt.Sprintf("%v %d", h.Hand, h.bid)
+}
+
+func HandFrom(token parse.Token) (Hand, error) {
+	h := make(Hand, len(token.Text))
+	for i, label := range []byte(token.Text) {
+		if !strings.ContainsRune(Deck, rune(label)) {
+			return nil, token.Slice(i, i+1).Errorf("unknown card %q", label)
+		}
+		h[i] = Card(label)
 	}
-	return h
+	return h, nil
 }
 
-func (h *Hand) isFiveOfAKind() bool {
-	countsPerKind := countsOf(h[:]...)
-	return len(countsPerKind) == 1
+func (h Hand) String() string {
+	return string(h)
 }
 
-func (h *Hand) isFourOfAKind() bool {
-	countsPerKind := countsOf(h[:]...)
-	if len(countsPerKind) != 2 {
-		return false
-	}
-	counts := valuesOf(countsPerKind)
-	slices.Sort(counts)
-	return slices.Equal(counts, []int{1, 4})
+// Type is the type of a hand: the numbers of cards of each kind, in decreasing order, e.g. [3 2] for a full house.
+// Types compare lexicographically, so that the order of the types of hands of five cards is the one of the puzzle and
+// extends to hands of any size.
+type Type []int
+
+var typeNames = map[string]string{
+	"[5]":         "fiveOfAKind",
+	"[4 1]":       "fourOfAKind",
+	"[3 2]":       "fullHouse",
+	"[3 1 1]":     "threeOfAKind",
+	"[2 2 1]":     "twoPair",
+	"[2 1 1 1]":   "onePair",
+	"[1 1 1 1 1]": "highCard",
 }
 
-func (h *Hand) isFullHouse() bool {
-	countsPerKind := countsOf(h[:]...)
-	if len(countsPerKind) != 2 {
-		return false
-	}
-	counts := valuesOf(countsPerKind)
-	slices.Sort(counts)
-	return slices.Equal(counts, []int{2, 3})
+// Compare compares the type with the given one.
+func (t Type) Compare(other Type) int {
+	return slices.Compare(t, other)
 }
 
-func (h *Hand) isThreeOfAKind() bool {
-	countsPerKind := countsOf(h[:]...)
-	if len(countsPerKind) != 3 {
-		return false
+// String returns the name of the type for hands of five cards, e.g. "fullHouse", or the counts otherwise, e.g.
+// "3+3+1".
+func (t Type) String() string {
+	signature := fmt.Sprint([]int(t))
+	if name, known := typeNames[signature]; known {
+		return name
 	}
-	counts := valuesOf(countsPerKind)
-	slices.Sort(counts)
-	return slices.Equal(counts, []int{1, 1, 3})
+	return strings.ReplaceAll(strings.Trim(signature, "[]"), " ", "+")
 }
 
-func (h *Hand) isTwoPair() bool {
-	countsPerKind := countsOf(h[:]...)
-	if len(countsPerKind) != 3 {
-		return false
-	}
-	counts := valuesOf(countsPerKind)
-	slices.Sort(counts)
-	return slices.Equal(counts, []int{1, 2, 2})
+// Classification is the type of a hand under some rules, along with the card its wildcards stand for, if it has any.
+type Classification struct {
+	Type       Type
+	Wildcards  int
+	Substitute Card
 }
 
-func (h *Hand) isOnePair() bool {
-	countsPerKind := countsOf(h[:]...)
-	return len(countsPerKind) == 4
+// Classify returns the type of the given hand under these rules. The wildcards stand for the most frequent other card,
+// the strongest one in case of tie, which gives the strongest type.
+func (r Rules) Classify(hand Hand) Classification {
+	counts := make(map[Card]int)
+	var classification Classification
+	for _, card := range hand {
+		if r.isWildcard(card) {
+			classification.Wildcards++
+		} else {
+			counts[card]++
+		}
+	}
+	if classification.Wildcards > 0 {
+		classification.Substitute = r.order[len(r.order)-1]
+		for card, count := range counts {
+			best := counts[classification.Substitute]
+			if count > best || count == best && r.strengths[card] > r.strengths[classification.Substitute] {
+				classification.Substitute = card
+			}
+		}
+		counts[classification.Substitute] += classification.Wildcards
+	}
+	classification.Type = slices.SortedFunc(maps.Values(counts), func(a, b int) int { return cmp.Compare(b, a) })
+	return classification
 }
 
-func (h *Hand) isHighCard() bool {
-	countsPerKind := countsOf(h[:]...)
-	return len(countsPerKind) == 5
+// RankedHand is a hand with its bid, classified once under some rules.
+type RankedHand struct {
+	HandWithBid
+	Classification
 }
 
-func (h *Hand) Type() int {
-	if h.isFiveOfAKind() {
-		return fiveOfAKind
-	}
-	if h.isFourOfAKind() {
-		return fourOfAKind
-	}
-	if h.isFullHouse() {
-		return fullHouse
-	}
-	if h.isThreeOfAKind() {
-		return threeOfAKind
-	}
-	if h.isTwoPair() {
-		return twoPair
-	}
-	if h.isOnePair() {
-		return onePair
+// Rank classifies the given hands under these rules and returns them from the weakest to the strongest.
+func (r Rules) Rank(hands []HandWithBid) []RankedHand {
+	ranked := make([]RankedHand, len(hands))
+	for i, hand := range hands {
+		ranked[i] = RankedHand{hand, r.Classify(hand.Hand)}
 	}
-	return highCard
+	slices.SortStableFunc(ranked, r.compare)
+	return ranked
 }
 
-func (h *Hand) TypeAsString() string {
-	if h.isFiveOfAKind() {
-		return "fiveOfAKind"
-	}
-	if h.isFourOfAKind() {
-		return "fourOfAKind"
+// compare compares the given hands under these rules: first by type, then card by card.
+func (r Rules) compare(a, b RankedHand) int {
+	if typeComparison := a.Type.Compare(b.Type); typeComparison != 0 {
+		return typeComparison
 	}
-	if h.isFullHouse() {
-		return "fullHouse"
+	if i, differ := firstDifference(a.Hand, b.Hand); differ {
+		return cmp.Compare(r.strength(a.Hand, i), r.strength(b.Hand, i))
 	}
-	if h.isThreeOfAKind() {
-		return "threeOfAKind"
-	}
-	if h.isTwoPair() {
-		return "twoPair"
-	}
-	if h.isOnePair() {
-		return "onePair"
+	return cmp.Compare(len(a.Hand), len(b.Hand))
+}
+
+// firstDifference returns the index of the first card differing between the given hands, if any.
+func firstDifference(a, b Hand) (int, bool) {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i, true
+		}
 	}
-	return "highCard"
+	return 0, false
 }
 
-func countsOf(items ...Card) map[Card]int {
-	counts := make(map[Card]int)
-	for _, item := range items {
-		counts[item]++
+func (r Rules) strength(hand Hand, i int) int {
+	return r.strengths[hand[i]]
+}
+
+// substituted returns the hand with its wildcards replaced by the card they stand for.
+func (h RankedHand) substituted(rules Rules) Hand {
+	substituted := slices.Clone(h.Hand)
+	for i, card := range substituted {
+		if rules.isWildcard(card) {
+			substituted[i] = h.Substitute
+		}
 	}
-	if jokerCount := counts[joker]; jokerCount > 0 {
-		delete(counts, joker)
-		// find the best card the jokers should represent
-		var bestCard Card
-		for card, count := range counts {
-			if count > counts[bestCard] || counts[bestCard] <= 1 && card > bestCard {
-				bestCard = card
-			}
+	return substituted
+}
+
+// Ranking is a list of hands ranked under some rules, from the weakest to the strongest.
+type Ranking struct {
+	rules Rules
+	hands []RankedHand
+}
+
+// String explains the ranking, one hand per line with its rank, its type, what its wildcards stand for and why it
+// ranks above the previous hand, e.g.:
+//
+//	1: 32T3K 765, onePair
+//	2: KK677 28, twoPair, above 32T3K: stronger type
+//	3: T55J5 684, fourOfAKind with T5555, above KK677: stronger type
+//	4: QQQJA 483, fourOfAKind with QQQQA, above T55J5: same type, stronger card 1 (Q > T)
+//	5: KTJJT 220, fourOfAKind with KTTTT, above QQQJA: same type, stronger card 1 (K > Q)
+func (r Ranking) String() string {
+	sb := strings.Builder{}
+	for i, hand := range r.hands {
+		fmt.Fprintf(&sb, "%d: %v, %v", i+1, hand.HandWithBid, hand.Type)
+		if hand.Wildcards > 0 {
+			fmt.Fprintf(&sb, " with %v", hand.substituted(r.rules))
+		}
+		if i > 0 {
+			fmt.Fprintf(&sb, ", above %v: %s", r.hands[i-1].Hand, r.rules.explainAbove(hand, r.hands[i-1]))
 		}
-		counts[bestCard] += jokerCount
+		sb.WriteByte('\n')
 	}
-	return counts
+	return sb.String()
 }
 
-func valuesOf[K, V comparable](m map[K]V) []V {
-	values := make([]V, 0, len(m))
-	for _, value := range m {
-		values = append(values, value)
+// explainAbove explains why the first given hand ranks above the second one.
+func (r Rules) explainAbove(hand, previous RankedHand) string {
+	if hand.Type.Compare(previous.Type) != 0 {
+		return "stronger type"
+	}
+	if i, differ := firstDifference(hand.Hand, previous.Hand); differ {
+		return fmt.Sprintf("same type, stronger card %d (%c > %c)", i+1, hand.Hand[i], previous.Hand[i])
+	}
+	if len(hand.Hand) != len(previous.Hand) {
+		return "same type, more cards"
 	}
-	return values
+	return "tie, listed after"
 }
 
-//go:embed input.txt
-var input string
+func init() {
+	registry.Register(2023, 7, registry.Solver[[]HandWithBid]{
+		Parse: handsWithBidFrom,
+		Part1: func(hands []HandWithBid) (any, error) { return totalWinnings(hands, ClassicRules), nil },
+		Part2: func(hands []HandWithBid) (any, error) { return totalWinnings(hands, JokerRules), nil },
+	})
+}
 
-func main() {
-	lines := strings.Split(input, "\n")
+func handsWithBidFrom(input string) ([]HandWithBid, error) {
+	lines := parse.Lines(input)
 	hands := make([]HandWithBid, len(lines))
 	for i, line := range lines {
-		fields := strings.Fields(line)
-		hand := HandFrom(fields[0])
-		bid, _ := strconv.Atoi(fields[1])
+		fields, err := line.ExpectFields(2)
+		if err != nil {
+			return nil, err
+		}
+		hand, err := HandFrom(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		bid, err := fields[1].Int()
+		if err != nil {
+			return nil, err
+		}
 		hands[i] = HandWithBid{hand, bid}
 	}
+	return hands, nil
+}
 
-	for _, hand := range hands {
-		fmt.Println(hand, " -> ", hand.TypeAsString())
-	}
-
-	sort.Slice(hands, func(cardAIndex, cardBIndex int) bool {
-		cardA := hands[cardAIndex].Hand
-		cardB := hands[cardBIndex].Hand
-		if cardA.Type() == cardB.Type() {
-			for i := 0; i < len(cardA); i++ {
-				if cardA[i] == cardB[i] {
-					continue
-				}
-				return cardA[i] < cardB[i]
-			}
-		}
-		return cardA.Type() < cardB.Type()
-	})
-	fmt.Println("Sorted hands: ", hands)
+// totalWinnings returns the total winnings of the given hands ranked under the given rules.
+func totalWinnings(hands []HandWithBid, rules Rules) int {
+	ranked := rules.Rank(hands)
+	slog.Debug("ranked hands", "rules", rules, "ranking", Ranking{rules, ranked})
 
 	winnings := 0
-	for i, hand := range hands {
+	for i, hand := range ranked {
 		winnings += hand.bid * (i + 1)
 	}
-	fmt.Println("Winnings: ", winnings)
+	return winnings
 }
@@ -0,0 +1,104 @@
+package day07
+
+import (
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/parse"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 7)
+}
+
+func TestUserDefinedRules(t *testing.T) {
+	// aces are the weakest cards, queens and threes are wild
+	rules, err := NewRules("upside down", "A23456789TJKQ", "Q3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if winnings := totalWinnings(aoctest.ParseExample(t, handsWithBidFrom), rules); winnings != 7722 {
+		t.Errorf("expected 7722, got %d", winnings)
+	}
+	hand := mustHand(t, "QQQJA")
+	if wild, classic := rules.Classify(hand), ClassicRules.Classify(hand); wild.Substitute != 'J' ||
+		wild.Type.String() != "fourOfAKind" || classic.Type.String() != "threeOfAKind" {
+		t.Errorf("expected %v to be a four of a kind with wild queens, got %v and %v", hand, wild, classic)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		hand       string
+		rules      Rules
+		expected   string
+		substitute Card
+	}{
+		{"32T3K", ClassicRules, "onePair", 0},
+		{"KTJJT", ClassicRules, "twoPair", 0},
+		{"KTJJT", JokerRules, "fourOfAKind", 'T'},
+		{"JJJJJ", JokerRules, "fiveOfAKind", 'A'},
+		{"2345J", JokerRules, "onePair", '5'},
+		{"AAAKKKQ", ClassicRules, "3+3+1", 0},
+		{"AAJ", JokerRules, "3", 'A'},
+		{"A2", ClassicRules, "1+1", 0},
+	}
+	for _, test := range tests {
+		classification := test.rules.Classify(mustHand(t, test.hand))
+		if classification.Type.String() != test.expected || classification.Substitute != test.substitute {
+			t.Errorf("%s under %v rules: expected %s with %q, got %v", test.hand, test.rules, test.expected,
+				test.substitute, classification)
+		}
+	}
+}
+
+func TestRankArbitraryHandSizes(t *testing.T) {
+	hands, err := handsWithBidFrom("AAAKKKQ 1\nAAAAKQ2 2\nKKKAAAQ 3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// four of a kind beats two threes of a kind, which are sorted card by card
+	if winnings := totalWinnings(hands, ClassicRules); winnings != 3*1+1*2+2*3 {
+		t.Errorf("expected %d, got %d", 3*1+1*2+2*3, winnings)
+	}
+}
+
+func TestRankingString(t *testing.T) {
+	ranking := Ranking{JokerRules, JokerRules.Rank(aoctest.ParseExample(t, handsWithBidFrom))}
+	expected := `1: 32T3K 765, onePair
+2: KK677 28, twoPair, above 32T3K: stronger type
+3: T55J5 684, fourOfAKind with T5555, above KK677: stronger type
+4: QQQJA 483, fourOfAKind with QQQQA, above T55J5: same type, stronger card 1 (Q > T)
+5: KTJJT 220, fourOfAKind with KTTTT, above QQQJA: same type, stronger card 1 (K > Q)
+`
+	if actual := ranking.String(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	tests := map[string]struct{ order, wildcards string }{
+		"unknown card":     {"23456789TJQKX", ""},
+		"duplicate card":   {"23456789TJQKK", ""},
+		"missing card":     {"23456789TJQK", ""},
+		"unknown wildcard": {"23456789TJQKA", "X"},
+	}
+	for name, test := range tests {
+		if _, err := NewRules(name, test.order, test.wildcards); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func mustHand(t *testing.T, cards string) Hand {
+	t.Helper()
+	hand, err := HandFrom(parse.Token{Text: cards})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hand
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 7)
+}
@@ -1,10 +1,14 @@
-package main
+package day08
 
 import (
-	_ "embed"
+	"context"
 	"fmt"
+	"log/slog"
+	"math/big"
 	"regexp"
-	"strings"
+
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
 const (
@@ -12,10 +16,17 @@ const (
 	right = Direction(1)
 )
 
-var crossingRe = regexp.MustCompile(`(?P<location>\w+) = \((?P<left>\w+), (?P<right>\w+)\)`)
+var crossingRe = regexp.MustCompile(`^(?P<location>\w+) = \((?P<left>\w+), (?P<right>\w+)\)$`)
 
 type Direction int
 
+func (d Direction) String() string {
+	if d == left {
+		return "L"
+	}
+	return "R"
+}
+
 type Location string
 
 type Crossing struct {
@@ -29,113 +40,96 @@ type Puzzle struct {
 	crossings  map[Location]Crossing
 }
 
-func (puzzle *Puzzle) RequiredSteps() int {
-	steps := 0
-	for crossing := puzzle.crossings["AAA"]; crossing.location != "ZZZ"; steps++ {
-		if puzzle.directions[steps%len(puzzle.directions)] == left {
-			crossing = puzzle.crossings[crossing.onLeft]
-		} else {
-			crossing = puzzle.crossings[crossing.onRight]
-		}
-	}
-	return steps
+func (puzzle *Puzzle) String() string {
+	return fmt.Sprintf("%d directions, %d crossings", len(puzzle.directions), len(puzzle.crossings))
 }
 
-func (puzzle *Puzzle) RequiredStepsForAGhost() int64 {
-	var startCrossings []Crossing
-	for location, crossing := range puzzle.crossings {
-		if strings.HasSuffix(string(location), "A") {
-			startCrossings = append(startCrossings, crossing)
-		}
-	}
-
-	stepsPerStartPoint := make([]int64, len(startCrossings))
-	for crossings, steps, remaining := startCrossings, int64(0), len(startCrossings); remaining > 0; steps++ {
-		direction := puzzle.directions[steps%int64(len(puzzle.directions))]
-		for i, crossing := range crossings {
-			if strings.HasSuffix(string(crossing.location), "Z") {
-				if stepsPerStartPoint[i] == 0 {
-					stepsPerStartPoint[i] = steps
-					remaining--
-				}
-				continue
-			}
-			if direction == left {
-				crossings[i] = puzzle.crossings[crossing.onLeft]
-			} else {
-				crossings[i] = puzzle.crossings[crossing.onRight]
-			}
-		}
-	}
-
-	if len(stepsPerStartPoint) == 1 {
-		return stepsPerStartPoint[0]
-	}
-
-	return lcm(stepsPerStartPoint[0], stepsPerStartPoint[1], stepsPerStartPoint[2:]...)
+// RequiredSteps returns the number of steps required to go from AAA to ZZZ.
+func (puzzle *Puzzle) RequiredSteps() (*big.Int, error) {
+	return puzzle.Walk(context.Background(), Walk{Start: Is("AAA"), Goal: Is("ZZZ")})
 }
 
-func gcd(a, b int64) int64 {
-	for b != 0 {
-		t := b
-		b = a % b
-		a = t
-	}
-	return a
+// RequiredStepsForAGhost returns the number of steps required for all the ghosts, starting from the A-suffixed
+// locations, to be on a Z-suffixed location at the same time.
+func (puzzle *Puzzle) RequiredStepsForAGhost() (*big.Int, error) {
+	return puzzle.Walk(context.Background(), Walk{Start: isStart, Goal: isEnd})
 }
 
-func lcm(a, b int64, integers ...int64) int64 {
-	result := a * b / gcd(a, b)
-	for i := 0; i < len(integers); i++ {
-		result = lcm(result, integers[i])
+func ParsePuzzle(input string) (*Puzzle, error) {
+	sections, err := parse.ExpectBlocks(input, 2)
+	if err != nil {
+		return nil, err
 	}
-	return result
-}
-
-func ParsePuzzle(input string) *Puzzle {
-	sections := strings.Split(input, "\n\n")
-	directions := parseDirections(sections[0])
-	crossings := parseCrossings(sections[1])
-	return &Puzzle{directions, crossings}
+	if len(sections[0]) != 1 {
+		return nil, sections[0][1].Errorf("expected a single line of directions")
+	}
+	directions, err := parseDirections(sections[0][0])
+	if err != nil {
+		return nil, err
+	}
+	crossings, err := parseCrossings(sections[1])
+	if err != nil {
+		return nil, err
+	}
+	return &Puzzle{directions, crossings}, nil
 }
 
-func parseDirections(s string) []Direction {
-	directions := make([]Direction, len(s))
-	for i, r := range s {
-		if r == 'L' {
+func parseDirections(line parse.Token) ([]Direction, error) {
+	directions := make([]Direction, len(line.Text))
+	for i, r := range line.Text {
+		switch r {
+		case 'L':
 			directions[i] = left
-		} else {
+		case 'R':
 			directions[i] = right
+		default:
+			return nil, line.Slice(i, i+1).Errorf("unknown direction %q, expected 'L' or 'R'", r)
 		}
 	}
-	return directions
+	return directions, nil
 }
 
-func parseCrossings(s string) map[Location]Crossing {
-	lines := strings.Split(s, "\n")
+func parseCrossings(lines []parse.Token) (map[Location]Crossing, error) {
 	crossings := make(map[Location]Crossing)
 	for _, line := range lines {
-		crossing := parseCrossing(line)
+		crossing, err := parseCrossing(line)
+		if err != nil {
+			return nil, err
+		}
 		crossings[crossing.location] = crossing
 	}
-	return crossings
+	return crossings, nil
 }
 
-func parseCrossing(s string) Crossing {
-	matches := crossingRe.FindStringSubmatch(s)
+func parseCrossing(line parse.Token) (Crossing, error) {
+	matches := crossingRe.FindStringSubmatch(line.Text)
+	if matches == nil {
+		return Crossing{}, line.Errorf("expected a crossing, e.g. \"AAA = (BBB, CCC)\"")
+	}
 	return Crossing{
 		location: Location(matches[crossingRe.SubexpIndex("location")]),
 		onLeft:   Location(matches[crossingRe.SubexpIndex("left")]),
 		onRight:  Location(matches[crossingRe.SubexpIndex("right")]),
-	}
+	}, nil
 }
 
-//go:embed input.txt
-var input string
-
-func main() {
-	puzzle := ParsePuzzle(input)
-	fmt.Println("Puzzle: ", puzzle)
-	fmt.Println("Required steps: ", puzzle.RequiredSteps())
-	fmt.Println("Required steps for a ghost: ", puzzle.RequiredStepsForAGhost())
+func init() {
+	registry.Register(2023, 8, registry.Solver[*Puzzle]{
+		Parse: ParsePuzzle,
+		Part1: func(puzzle *Puzzle) (any, error) {
+			slog.Debug("parsed puzzle", "puzzle", puzzle)
+			steps, err := puzzle.RequiredSteps()
+			if err != nil {
+				return nil, err
+			}
+			return steps, nil
+		},
+		Part2: func(puzzle *Puzzle) (any, error) {
+			steps, err := puzzle.RequiredStepsForAGhost()
+			if err != nil {
+				return nil, err
+			}
+			return steps, nil
+		},
+	})
 }
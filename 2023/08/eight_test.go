@@ -0,0 +1,182 @@
+package day08
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 8)
+}
+
+func TestRequiredStepsForAGhost(t *testing.T) {
+	const (
+		// on Z at even steps from step 2
+		ghost1 = "11A = (11B, 11B)\n11B = (11Z, 11Z)\n11Z = (11C, 11C)\n11C = (11Z, 11Z)\n"
+		// on Z at steps 1 + 3k
+		ghost2 = "22A = (22Z, 22Z)\n22Z = (22B, 22B)\n22B = (22C, 22C)\n22C = (22Z, 22Z)\n"
+		// on Z at odd steps
+		ghost3 = "33A = (33Z, 33Z)\n33Z = (33B, 33B)\n33B = (33Z, 33Z)\n"
+		// on Z at step 1 only
+		ghost4 = "44A = (44Z, 44Z)\n44Z = (44B, 44B)\n44B = (44B, 44B)\n"
+	)
+	tests := map[string]struct {
+		network  string
+		expected string
+	}{
+		"loops not starting at the first goal": {ghost1 + ghost2, "4"},
+		"goal in a tail":                       {ghost2 + ghost4, "1"},
+		"incompatible loops":                   {ghost1 + ghost3, ""},
+		"goal in a tail only":                  {ghost1 + ghost4, ""},
+	}
+	for name, test := range tests {
+		puzzle, err := ParsePuzzle("L\n\n" + test.network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps, err := puzzle.RequiredStepsForAGhost()
+		switch {
+		case test.expected == "" && !errors.Is(err, ErrNoCommonStep):
+			t.Errorf("%s: expected ErrNoCommonStep, got %v, %v", name, steps, err)
+		case test.expected != "" && (err != nil || steps.String() != test.expected):
+			t.Errorf("%s: expected %s, got %v, %v", name, test.expected, steps, err)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	puzzle := aoctest.ParseFile(t, "input-example-2.txt", ParsePuzzle)
+	tests := map[string]struct {
+		walk     Walk
+		expected string
+		err      error
+	}{
+		"custom predicates":    {Walk{Start: Is("22B"), Goal: Is("22B")}, "0", nil},
+		"lone walker":          {Walk{Start: Is("22A"), Goal: isEnd}, "3", nil},
+		"within budget":        {Walk{Start: isStart, Goal: isEnd, MaxSteps: 6}, "6", nil},
+		"beyond budget":        {Walk{Start: isStart, Goal: isEnd, MaxSteps: 5}, "", ErrBudgetExceeded},
+		"beyond lone budget":   {Walk{Start: Is("22A"), Goal: isEnd, MaxSteps: 2}, "", ErrBudgetExceeded},
+		"unreachable goal":     {Walk{Start: Is("11A"), Goal: Is("22Z")}, "", ErrUnreachableGoal},
+		"unreachable for some": {Walk{Start: isStart, Goal: Is("XXX")}, "", ErrUnreachableGoal},
+		"start in a loop":      {Walk{Start: Is("11Z"), Goal: Is("11B")}, "1", nil},
+	}
+	for name, test := range tests {
+		steps, err := puzzle.Walk(context.Background(), test.walk)
+		switch {
+		case test.err != nil && !errors.Is(err, test.err):
+			t.Errorf("%s: expected %v, got %v, %v", name, test.err, steps, err)
+		case test.err == nil && (err != nil || steps.String() != test.expected):
+			t.Errorf("%s: expected %s, got %v, %v", name, test.expected, steps, err)
+		}
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	dangling, err := ParsePuzzle("LR\n\nAAA = (BBB, ZZZ)\nBBB = (AAA, CCC)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dangling.RequiredSteps(); !errors.Is(err, ErrDanglingLocation) {
+		t.Errorf("expected ErrDanglingLocation, got %v", err)
+	}
+	if _, err := aoctest.ParseFile(t, "input-example-2.txt", ParsePuzzle).RequiredSteps(); !errors.Is(err, ErrNoStart) {
+		t.Errorf("expected ErrNoStart without AAA location, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	puzzle := aoctest.ParseFile(t, "input-example.txt", ParsePuzzle)
+	if _, err := puzzle.Walk(ctx, Walk{Start: Is("AAA"), Goal: Is("ZZZ")}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	puzzle := aoctest.ParseFile(t, "input-example-2.txt", ParsePuzzle)
+	path, err := puzzle.Path("22A", isEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := puzzle.WriteDOT(&out, ExportOptions{Ends: true, Path: path, Loops: true}); err != nil {
+		t.Fatal(err)
+	}
+	dot := out.String()
+	for _, expected := range []string{
+		"digraph network {\n",
+		`  "11A" [style=filled, fillcolor=palegreen];` + "\n",
+		`  "11Z" [style=filled, fillcolor=lightcoral];` + "\n",
+		`  "11A" -> "11B" [label="L"];` + "\n",
+		`  "22A" -> "22B" [label="L", color=blue, penwidth=2];` + "\n",
+		`  "22B" [color=blue];` + "\n",
+		`  "22C" -> "22Z" [label="L,R", color=blue, penwidth=2];` + "\n",
+		"  subgraph cluster_0 {\n    label=\"loop of [11A]\";\n    \"11B\";\n    \"11Z\";\n  }\n",
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("expected %q in:\n%s", expected, dot)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	puzzle := aoctest.ParseFile(t, "input-example-2.txt", ParsePuzzle)
+	var out strings.Builder
+	if err := puzzle.WriteJSON(&out, ExportOptions{Ends: true, Loops: true}); err != nil {
+		t.Fatal(err)
+	}
+	var network struct {
+		Directions string
+		Nodes      []struct {
+			Location   Location
+			Start, End bool
+		}
+		Loops []Loop
+	}
+	if err := json.Unmarshal([]byte(out.String()), &network); err != nil {
+		t.Fatal(err)
+	}
+	if network.Directions != "LR" || len(network.Nodes) != 8 || !network.Nodes[0].Start || !network.Nodes[2].End {
+		t.Errorf("unexpected network %+v", network)
+	}
+	expectedLoops := []Loop{
+		{[]Location{"11A"}, []Location{"11B", "11Z"}},
+		{[]Location{"22A"}, []Location{"22B", "22C", "22Z"}},
+	}
+	if !slices.EqualFunc(network.Loops, expectedLoops, func(a, b Loop) bool {
+		return slices.Equal(a.Ghosts, b.Ghosts) && slices.Equal(a.Locations, b.Locations)
+	}) {
+		t.Errorf("expected loops %v, got %v", expectedLoops, network.Loops)
+	}
+}
+
+func TestExportWithPredicates(t *testing.T) {
+	puzzle := aoctest.ParseFile(t, "input-example-2.txt", ParsePuzzle)
+	options := ExportOptions{Start: Is("22A"), Goal: Is("22Z"), Ends: true, Loops: true}
+	var out strings.Builder
+	if err := puzzle.WriteDOT(&out, options); err != nil {
+		t.Fatal(err)
+	}
+	dot := out.String()
+	if strings.Contains(dot, `"11A" [style=filled`) || strings.Contains(dot, `"11Z" [style=filled`) {
+		t.Errorf("expected 11A and 11Z not to be highlighted in:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"22A" [style=filled, fillcolor=palegreen]`) || strings.Contains(dot, "loop of [11A]") {
+		t.Errorf("expected only 22A to start and loop in:\n%s", dot)
+	}
+	loops, err := puzzle.Loops(options.Start, options.Goal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loops) != 1 || !slices.Equal(loops[0].Ghosts, []Location{"22A"}) {
+		t.Errorf("expected a single loop of 22A, got %v", loops)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 8)
+}
@@ -0,0 +1,205 @@
+package day08
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/super7ramp/aoc/search"
+)
+
+// ExportOptions selects what is highlighted when exporting the network.
+type ExportOptions struct {
+	// Start and Goal select the start and end locations, like in Walk. If nil, the start locations are the ones
+	// suffixed with A and the end locations the ones suffixed with Z.
+	Start func(Location) bool
+	Goal  func(Location) bool
+	// Ends highlights the start and end locations.
+	Ends bool
+	// Path highlights the locations and the moves of a walker, e.g. as returned by Path.
+	Path []Location
+	// Loops highlights the strongly connected components of the network in which the ghosts, starting from the start
+	// locations, end up looping.
+	Loops bool
+}
+
+// ends returns the predicates selecting the start and end locations.
+func (options ExportOptions) ends() (start, goal func(Location) bool) {
+	start, goal = options.Start, options.Goal
+	if start == nil {
+		start = isStart
+	}
+	if goal == nil {
+		goal = isEnd
+	}
+	return start, goal
+}
+
+// Loop is a strongly connected component of the network in which some ghosts end up looping.
+type Loop struct {
+	Ghosts    []Location `json:"ghosts"`
+	Locations []Location `json:"locations"`
+}
+
+// Path returns the locations a walker goes through from the given start until it reaches a goal, both included, or an
+// error if it never does.
+func (puzzle *Puzzle) Path(start Location, isGoal func(Location) bool) ([]Location, error) {
+	cycle, err := puzzle.ghostCycle(context.Background(), start, isGoal, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	hits := slices.Concat(cycle.TailHits, cycle.LoopHits)
+	if len(hits) == 0 {
+		return nil, fmt.Errorf("%w from %s", ErrUnreachableGoal, start)
+	}
+	return cycle.locations[:hits[0]+1], nil
+}
+
+// Loops returns the strongly connected components of the network in which the ghosts, starting from the locations
+// satisfying start and heading for the ones satisfying goal, end up looping, with the ghosts looping in each of them.
+func (puzzle *Puzzle) Loops(start, goal func(Location) bool) ([]Loop, error) {
+	locations := slices.Sorted(maps.Keys(puzzle.crossings))
+	components := search.StronglyConnectedComponents(locations, puzzle.neighbors)
+	componentOf := make(map[Location]int)
+	for i, component := range components {
+		for _, location := range component {
+			componentOf[location] = i
+		}
+	}
+	var loops []Loop
+	loopOf := make(map[int]int)
+	for _, location := range locations {
+		if !start(location) {
+			continue
+		}
+		cycle, err := puzzle.ghostCycle(context.Background(), location, goal, 0, false)
+		if err != nil {
+			return nil, err
+		}
+		component := componentOf[cycle.locations[cycle.Start]]
+		i, known := loopOf[component]
+		if !known {
+			i = len(loops)
+			loopOf[component] = i
+			loops = append(loops, Loop{Locations: slices.Sorted(slices.Values(components[component]))})
+		}
+		loops[i].Ghosts = append(loops[i].Ghosts, location)
+	}
+	return loops, nil
+}
+
+// neighbors returns the locations reachable in one step from the given one.
+func (puzzle *Puzzle) neighbors(location Location) []Location {
+	crossing, known := puzzle.crossings[location]
+	switch {
+	case !known:
+		return nil
+	case crossing.onLeft == crossing.onRight:
+		return []Location{crossing.onLeft}
+	default:
+		return []Location{crossing.onLeft, crossing.onRight}
+	}
+}
+
+func isStart(location Location) bool {
+	return strings.HasSuffix(string(location), "A")
+}
+
+func isEnd(location Location) bool {
+	return strings.HasSuffix(string(location), "Z")
+}
+
+// WriteDOT writes the network as a Graphviz directed graph, each crossing leading to its left and right locations:
+// start locations are green, end locations are red, the moves of the path are blue and the loops are clusters.
+func (puzzle *Puzzle) WriteDOT(w io.Writer, options ExportOptions) error {
+	start, goal := options.ends()
+	var loops []Loop
+	if options.Loops {
+		var err error
+		if loops, err = puzzle.Loops(start, goal); err != nil {
+			return err
+		}
+	}
+	moves := make(map[[2]Location]bool)
+	for i := 1; i < len(options.Path); i++ {
+		moves[[2]Location{options.Path[i-1], options.Path[i]}] = true
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("digraph network {\n")
+	for _, location := range slices.Sorted(maps.Keys(puzzle.crossings)) {
+		switch {
+		case options.Ends && start(location):
+			fmt.Fprintf(&sb, "  %q [style=filled, fillcolor=palegreen];\n", location)
+		case options.Ends && goal(location):
+			fmt.Fprintf(&sb, "  %q [style=filled, fillcolor=lightcoral];\n", location)
+		case slices.Contains(options.Path, location):
+			fmt.Fprintf(&sb, "  %q [color=blue];\n", location)
+		}
+		crossing := puzzle.crossings[location]
+		edges := []struct {
+			to    Location
+			label string
+		}{{crossing.onLeft, "L"}, {crossing.onRight, "R"}}
+		if crossing.onLeft == crossing.onRight {
+			edges = edges[:1]
+			edges[0].label = "L,R"
+		}
+		for _, edge := range edges {
+			fmt.Fprintf(&sb, "  %q -> %q [label=%q", location, edge.to, edge.label)
+			if moves[[2]Location{location, edge.to}] {
+				sb.WriteString(", color=blue, penwidth=2")
+			}
+			sb.WriteString("];\n")
+		}
+	}
+	for i, loop := range loops {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n    label=%q;\n", i, fmt.Sprint("loop of ", loop.Ghosts))
+		for _, location := range loop.Locations {
+			fmt.Fprintf(&sb, "    %q;\n", location)
+		}
+		sb.WriteString("  }\n")
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes the network as a JSON object, e.g.:
+//
+//	{"directions":"LR","nodes":[{"location":"11A","left":"11B","right":"XXX","start":true},…],"loops":[…]}
+func (puzzle *Puzzle) WriteJSON(w io.Writer, options ExportOptions) error {
+	type node struct {
+		Location Location `json:"location"`
+		Left     Location `json:"left"`
+		Right    Location `json:"right"`
+		Start    bool     `json:"start,omitempty"`
+		End      bool     `json:"end,omitempty"`
+	}
+	network := struct {
+		Directions string     `json:"directions"`
+		Nodes      []node     `json:"nodes"`
+		Path       []Location `json:"path,omitempty"`
+		Loops      []Loop     `json:"loops,omitempty"`
+	}{Path: options.Path}
+	start, goal := options.ends()
+	for _, direction := range puzzle.directions {
+		network.Directions += direction.String()
+	}
+	for _, location := range slices.Sorted(maps.Keys(puzzle.crossings)) {
+		crossing := puzzle.crossings[location]
+		network.Nodes = append(network.Nodes, node{location, crossing.onLeft, crossing.onRight,
+			options.Ends && start(location), options.Ends && goal(location)})
+	}
+	if options.Loops {
+		var err error
+		if network.Loops, err = puzzle.Loops(start, goal); err != nil {
+			return err
+		}
+	}
+	return json.NewEncoder(w).Encode(network)
+}
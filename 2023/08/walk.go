@@ -0,0 +1,248 @@
+package day08
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/super7ramp/aoc/aocmath"
+)
+
+var (
+	// ErrNoStart is returned when no location matches the start of a walk.
+	ErrNoStart = errors.New("no start location")
+	// ErrDanglingLocation is returned when a walk leads to a location which has no crossing.
+	ErrDanglingLocation = errors.New("dangling location")
+	// ErrUnreachableGoal is returned when a walker never reaches a goal.
+	ErrUnreachableGoal = errors.New("goal unreachable")
+	// ErrNoCommonStep is returned when the walkers reach their goals, but never all at the same step.
+	ErrNoCommonStep = errors.New("walkers never meet their goals at the same step")
+	// ErrBudgetExceeded is returned when the walkers are not all on a goal within the maximum number of steps.
+	ErrBudgetExceeded = errors.New("step budget exceeded")
+)
+
+// contextCheckInterval is the number of steps between two checks of the cancellation of a walk.
+const contextCheckInterval = 1 << 12
+
+// Walk describes walkers starting together from all the locations satisfying Start, following the directions, until
+// they all are on a location satisfying Goal at the same step.
+type Walk struct {
+	Start func(Location) bool
+	Goal  func(Location) bool
+	// MaxSteps, if positive, is the maximum number of steps: it bounds both the answer and the number of steps each
+	// walker is followed.
+	MaxSteps int
+}
+
+// Is returns a predicate matching the given location.
+func Is(location Location) func(Location) bool {
+	return func(l Location) bool {
+		return l == location
+	}
+}
+
+// Walk returns the number of steps of the given walk. It returns ErrNoStart if no location matches the start,
+// ErrDanglingLocation, ErrUnreachableGoal, ErrNoCommonStep or ErrBudgetExceeded if the walk cannot end, or the error of
+// the context if it is done before. The number of steps is computed with arbitrary precision, since it may exceed
+// int64.
+//
+// Each walker eventually loops, since its state, i.e. its location and the index of its next instruction, can only
+// take a finite number of values. The steps at which the walkers are on a goal are either in the tail of their path,
+// before the loop, or periodic; the common steps in the loops are found with the Chinese remainder theorem.
+func (puzzle *Puzzle) Walk(ctx context.Context, walk Walk) (*big.Int, error) {
+	var starts []Location
+	for _, location := range slices.Sorted(maps.Keys(puzzle.crossings)) {
+		if walk.Start(location) {
+			starts = append(starts, location)
+		}
+	}
+	if len(starts) == 0 {
+		return nil, ErrNoStart
+	}
+	// a lone walker is done as soon as it reaches a goal, there is no need to find its loop
+	stopAtGoal := len(starts) == 1
+	cycles := make([]GhostCycle, len(starts))
+	for i, start := range starts {
+		cycle, err := puzzle.ghostCycle(ctx, start, walk.Goal, walk.MaxSteps, stopAtGoal)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("found walker cycle", "start", start, "cycle", cycle)
+		if cycle.Length > 0 && len(cycle.TailHits) == 0 && len(cycle.LoopHits) == 0 {
+			return nil, fmt.Errorf("%w from %s", ErrUnreachableGoal, start)
+		}
+		cycles[i] = cycle
+	}
+	steps, err := commonStep(ctx, cycles)
+	if err != nil {
+		return nil, err
+	}
+	if walk.MaxSteps > 0 && steps.Cmp(big.NewInt(int64(walk.MaxSteps))) > 0 {
+		return nil, fmt.Errorf("%w: %v steps required, %d allowed", ErrBudgetExceeded, steps, walk.MaxSteps)
+	}
+	return steps, nil
+}
+
+// GhostCycle describes the steps at which a walker, e.g. a ghost, is on a goal location: its path is a tail followed
+// by a loop, repeated forever.
+type GhostCycle struct {
+	// Start is the step at which the loop starts, i.e. the length of the tail.
+	Start int
+	// Length is the number of steps of the loop, or 0 if the walker was stopped before its loop was found: the tail is
+	// then all that is known of its path.
+	Length int
+	// TailHits are the steps of the tail at which the walker is on a goal.
+	TailHits []int
+	// LoopHits are the steps of the first run of the loop at which the walker is on a goal: the walker is on a goal at
+	// each of them plus any multiple of Length.
+	LoopHits []int
+	// locations are the locations of the walker at each step of the tail and of the first run of the loop.
+	locations []Location
+}
+
+func (c GhostCycle) String() string {
+	if c.Length == 0 {
+		return fmt.Sprintf("stopped after %d steps with goals at %v", c.Start, c.TailHits)
+	}
+	return fmt.Sprintf("tail of %d steps with goals at %v, loop of %d steps with goals at %v", c.Start, c.TailHits,
+		c.Length, c.LoopHits)
+}
+
+// isOnGoal returns true if the walker is on a goal at the given step, which must be in its tail if its loop is
+// unknown.
+func (c GhostCycle) isOnGoal(step int) bool {
+	if step < c.Start {
+		return slices.Contains(c.TailHits, step)
+	}
+	return slices.ContainsFunc(c.LoopHits, func(hit int) bool { return (step-hit)%c.Length == 0 })
+}
+
+// ghostCycle follows a walker from the given location until its state repeats. If maxSteps is positive, the walker is
+// stopped after maxSteps steps; if stopAtGoal is true, it is stopped on its first goal.
+func (puzzle *Puzzle) ghostCycle(ctx context.Context, start Location, isGoal func(Location) bool, maxSteps int,
+	stopAtGoal bool) (GhostCycle, error) {
+	type state struct {
+		location    Location
+		instruction int
+	}
+	firstSeen := make(map[state]int)
+	var hits []int
+	var locations []Location
+	location := start
+	for step := 0; ; step++ {
+		if step%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return GhostCycle{}, err
+			}
+		}
+		current := state{location, step % len(puzzle.directions)}
+		if loopStart, seen := firstSeen[current]; seen {
+			cycle := GhostCycle{Start: loopStart, Length: step - loopStart, locations: locations}
+			for _, hit := range hits {
+				if hit < loopStart {
+					cycle.TailHits = append(cycle.TailHits, hit)
+				} else {
+					cycle.LoopHits = append(cycle.LoopHits, hit)
+				}
+			}
+			return cycle, nil
+		}
+		firstSeen[current] = step
+		locations = append(locations, location)
+		if isGoal(location) {
+			hits = append(hits, step)
+		}
+		if maxSteps > 0 && step == maxSteps || stopAtGoal && len(hits) > 0 {
+			return GhostCycle{Start: step + 1, TailHits: hits, locations: locations}, nil
+		}
+		crossing, known := puzzle.crossings[location]
+		if !known {
+			return GhostCycle{}, fmt.Errorf("%w %s, reached from %s after %d steps", ErrDanglingLocation, location,
+				start, step)
+		}
+		if puzzle.directions[current.instruction] == left {
+			location = crossing.onLeft
+		} else {
+			location = crossing.onRight
+		}
+	}
+}
+
+// commonStep returns the first step at which all the walkers of the given cycles are on a goal.
+func commonStep(ctx context.Context, cycles []GhostCycle) (*big.Int, error) {
+	// before all the walkers are in their loops, the steps are few enough to be checked one by one
+	lastTailEnd := 0
+	stopped := false
+	for _, cycle := range cycles {
+		lastTailEnd = max(lastTailEnd, cycle.Start)
+		stopped = stopped || cycle.Length == 0
+	}
+	for step := range lastTailEnd {
+		if step%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if !slices.ContainsFunc(cycles, func(c GhostCycle) bool { return !c.isOnGoal(step) }) {
+			return big.NewInt(int64(step)), nil
+		}
+	}
+	if stopped {
+		return nil, fmt.Errorf("%w: walkers stopped after %d steps", ErrBudgetExceeded, lastTailEnd-1)
+	}
+
+	// then, each combination of one loop hit per walker gives a system of congruences
+	var best *big.Int
+	remainders := make([]int64, len(cycles))
+	moduli := make([]int64, len(cycles))
+	var combine func(i, lastHit int) error
+	combine = func(i, lastHit int) error {
+		if i == len(cycles) {
+			if step := firstSolution(remainders, moduli, lastHit); step != nil && (best == nil || step.Cmp(best) < 0) {
+				best = step
+			}
+			return ctx.Err()
+		}
+		moduli[i] = int64(cycles[i].Length)
+		for _, hit := range cycles[i].LoopHits {
+			remainders[i] = int64(hit)
+			if err := combine(i+1, max(lastHit, hit)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := combine(0, 0); err != nil {
+		return nil, err
+	}
+	if best == nil {
+		return nil, ErrNoCommonStep
+	}
+	return best, nil
+}
+
+// firstSolution returns the smallest solution of the given system of congruences which is at least the given minimum,
+// or nil if there is none.
+func firstSolution(remainders, moduli []int64, minimum int) *big.Int {
+	var x, m *big.Int
+	if smallX, smallM, err := aocmath.CRT(remainders, moduli); err == nil {
+		x, m = big.NewInt(smallX), big.NewInt(smallM)
+	} else if errors.Is(err, aocmath.ErrOverflow) {
+		if x, m, err = aocmath.BigCRT(remainders, moduli); err != nil {
+			return nil
+		}
+	} else {
+		return nil
+	}
+	// x is the smallest non-negative solution, the first one not before the last first hit is x + k*m
+	if shortfall := new(big.Int).Sub(big.NewInt(int64(minimum)), x); shortfall.Sign() > 0 {
+		k := new(big.Int).Add(shortfall, new(big.Int).Sub(m, big.NewInt(1)))
+		k.Div(k, m)
+		x.Add(x, k.Mul(k, m))
+	}
+	return x
+}
@@ -1,193 +1,179 @@
-package main
+package day11
 
 import (
-	_ "embed"
 	"fmt"
+	"iter"
+	"log/slog"
 	"slices"
-	"strings"
+
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
 )
 
 type Element rune
 
 const (
 	galaxy = Element('#')
+	empty  = Element('.')
 )
 
-type Position struct {
-	rowIndex    int
-	columnIndex int
-}
-
-func (p *Position) stepDistanceTo(other Position) int {
-	return abs(p.rowIndex-other.rowIndex) + abs(p.columnIndex-other.columnIndex)
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
+// Universe is an image of the universe. Space is sparse: only the positions of the galaxies are stored, so that it can
+// be expanded by any factor.
 type Universe struct {
-	space       [][]Element
-	columnCount int
-	rowCount    int
+	galaxies      []grid.Pos
+	width, height int
 }
 
+// String renders the universe, one row per line. It is meant for small universes only.
 func (s *Universe) String() string {
-	var builder strings.Builder
-	for _, row := range s.space {
-		for _, element := range row {
-			builder.WriteRune(rune(element))
-		}
-		builder.WriteRune('\n')
+	space := grid.New[Element](s.width, s.height)
+	for position := range space.Positions() {
+		space.Set(position, empty)
 	}
-	return builder.String()
+	for _, position := range s.galaxies {
+		space.Set(position, galaxy)
+	}
+	return space.String()
 }
 
-func (s *Universe) Expand() *Universe {
+// Expand returns the universe after expansion: each row and each column without galaxy is replaced by the given number
+// of rows or columns, e.g. 2 to double them. It returns an error if the factor is below 1.
+func (s *Universe) Expand(factor int) (*Universe, error) {
+	if factor < 1 {
+		return nil, fmt.Errorf("invalid expansion factor %d, expected at least 1", factor)
+	}
 	rowsWithoutGalaxy := s.RowsWithoutGalaxy()
-	expandedSpaceRowCount := s.rowCount + len(rowsWithoutGalaxy)
-
 	columnsWithoutGalaxy := s.ColumnsWithoutGalaxy()
-	expandedSpaceColumnCount := s.columnCount + len(columnsWithoutGalaxy)
-
-	expandedSpace := make([][]Element, expandedSpaceRowCount)
-	for rowIndex, addedRows := 0, 0; rowIndex < s.rowCount; rowIndex++ {
-		expandedSpace[rowIndex+addedRows] = make([]Element, expandedSpaceColumnCount)
-		for columnIndex, addedColumns := 0, 0; columnIndex < s.columnCount; columnIndex++ {
-			expandedSpace[rowIndex+addedRows][columnIndex+addedColumns] = s.space[rowIndex][columnIndex]
-			if slices.Contains(columnsWithoutGalaxy, columnIndex) {
-				expandedSpace[rowIndex+addedRows][columnIndex+addedColumns+1] = s.space[rowIndex][columnIndex]
-				addedColumns++
-			}
-		}
-		if slices.Contains(rowsWithoutGalaxy, rowIndex) {
-			expandedSpace[rowIndex+addedRows+1] = make([]Element, expandedSpaceColumnCount)
-			copy(expandedSpace[rowIndex+addedRows+1], expandedSpace[rowIndex+addedRows])
-			addedRows++
-		}
+	expanded := &Universe{
+		galaxies: make([]grid.Pos, len(s.galaxies)),
+		width:    s.width + len(columnsWithoutGalaxy)*(factor-1),
+		height:   s.height + len(rowsWithoutGalaxy)*(factor-1),
 	}
-	return &Universe{
-		space:       expandedSpace,
-		columnCount: s.columnCount + len(columnsWithoutGalaxy),
-		rowCount:    s.rowCount + len(rowsWithoutGalaxy),
+	for i, position := range s.galaxies {
+		// a galaxy is never on an empty row or column, so the search returns the number of them before the galaxy
+		columnsBefore, _ := slices.BinarySearch(columnsWithoutGalaxy, position.X)
+		rowsBefore, _ := slices.BinarySearch(rowsWithoutGalaxy, position.Y)
+		expanded.galaxies[i] = grid.Pos{
+			X: position.X + columnsBefore*(factor-1),
+			Y: position.Y + rowsBefore*(factor-1),
+		}
 	}
-
+	return expanded, nil
 }
 
+// RowsWithoutGalaxy returns the indexes of the rows without galaxy, sorted.
 func (s *Universe) RowsWithoutGalaxy() []int {
-	var rowsWithoutGalaxy []int
-	for rowIndex, row := range s.space {
-		if !slices.Contains(row, galaxy) {
-			rowsWithoutGalaxy = append(rowsWithoutGalaxy, rowIndex)
-		}
-	}
-	return rowsWithoutGalaxy
+	return withoutGalaxy(s.height, s.galaxies, func(p grid.Pos) int { return p.Y })
 }
 
+// ColumnsWithoutGalaxy returns the indexes of the columns without galaxy, sorted.
 func (s *Universe) ColumnsWithoutGalaxy() []int {
-	var columnsWithoutGalaxy []int
-	for columnIndex := 0; columnIndex < s.columnCount; columnIndex++ {
-		column := make([]Element, s.rowCount)
-		for rowIndex := 0; rowIndex < s.rowCount; rowIndex++ {
-			column[rowIndex] = s.space[rowIndex][columnIndex]
-		}
-		if !slices.Contains(column, galaxy) {
-			columnsWithoutGalaxy = append(columnsWithoutGalaxy, columnIndex)
-		}
-	}
-	return columnsWithoutGalaxy
+	return withoutGalaxy(s.width, s.galaxies, func(p grid.Pos) int { return p.X })
 }
 
-func (s *Universe) GalaxyPositions() [](Position) {
-	var galaxies []Position
-	for rowIndex, row := range s.space {
-		for columnIndex, element := range row {
-			if element == galaxy {
-				galaxies = append(galaxies, Position{rowIndex, columnIndex})
-			}
+func withoutGalaxy(size int, galaxies []grid.Pos, coordinate func(grid.Pos) int) []int {
+	hasGalaxy := make([]bool, size)
+	for _, position := range galaxies {
+		hasGalaxy[coordinate(position)] = true
+	}
+	var indexes []int
+	for index, found := range hasGalaxy {
+		if !found {
+			indexes = append(indexes, index)
 		}
 	}
-	return galaxies
+	return indexes
 }
 
-func UniverseFrom(input string) *Universe {
-	lines := strings.Split(input, "\n")
-	space := make([][]Element, len(lines))
-	for rowIndex, line := range lines {
-		space[rowIndex] = make([]Element, len(line))
-		for columnIndex, char := range line {
-			space[rowIndex][columnIndex] = Element(char)
-		}
-	}
-	width := len(lines[0])
-	height := len(lines)
-	return &Universe{space, width, height}
+// GalaxyPositions returns the positions of the galaxies, row by row.
+func (s *Universe) GalaxyPositions() []grid.Pos {
+	return slices.Clone(s.galaxies)
 }
 
-type FastExpansionUniverse struct {
-	galaxyPositions []Position
+// Distance returns the length of the shortest path between the galaxies of given indexes, moving up, down, left or
+// right.
+func (s *Universe) Distance(i, j int) int {
+	return s.galaxies[i].ManhattanDistance(s.galaxies[j])
 }
 
-func FastExpansionUniverseFrom(universe *Universe) *FastExpansionUniverse {
-	galaxyPositions := universe.GalaxyPositions()
-	columnsWithoutGalaxy := universe.ColumnsWithoutGalaxy()
-	rowsWithoutGalaxy := universe.RowsWithoutGalaxy()
+// Pair is a pair of galaxies, given by their indexes in GalaxyPositions.
+type Pair struct {
+	I, J int
+}
 
-	for i, galaxyPosition := range galaxyPositions {
-		var columnsWithoutGalaxyBeforeGalaxy int
-		for _, columnIndex := range columnsWithoutGalaxy {
-			if columnIndex < galaxyPosition.columnIndex {
-				columnsWithoutGalaxyBeforeGalaxy++
-			}
-		}
-		var rowsWithoutGalaxyBeforeGalaxy int
-		for _, rowIndex := range rowsWithoutGalaxy {
-			if rowIndex < galaxyPosition.rowIndex {
-				rowsWithoutGalaxyBeforeGalaxy++
+// Distances returns an iterator over the pairs of galaxies and their distances, computed on demand.
+func (s *Universe) Distances() iter.Seq2[Pair, int] {
+	return func(yield func(Pair, int) bool) {
+		for i := range s.galaxies {
+			for j := i + 1; j < len(s.galaxies); j++ {
+				if !yield(Pair{i, j}, s.Distance(i, j)) {
+					return
+				}
 			}
 		}
-		galaxyPositions[i] = Position{
-			galaxyPosition.rowIndex + rowsWithoutGalaxyBeforeGalaxy*999_999,
-			galaxyPosition.columnIndex + columnsWithoutGalaxyBeforeGalaxy*999_999,
-		}
 	}
-
-	return &FastExpansionUniverse{galaxyPositions}
 }
 
-//go:embed input.txt
-var input string
-
-func main() {
-	universe := UniverseFrom(input)
-	fmt.Println("Initial universe:")
-	fmt.Println(universe.String())
-
-	expandedSpace := universe.Expand()
-	fmt.Println("Expanded universe:")
-	fmt.Println(expandedSpace.String())
+// DistanceSum returns the sum of the distances between all the pairs of galaxies. Since the distance is the sum of the
+// distances along each axis, it sums the differences of the sorted coordinates on each axis, in O(n log n).
+func (s *Universe) DistanceSum() int {
+	xs := make([]int, len(s.galaxies))
+	ys := make([]int, len(s.galaxies))
+	for i, position := range s.galaxies {
+		xs[i], ys[i] = position.X, position.Y
+	}
+	return sortedDifferenceSum(xs) + sortedDifferenceSum(ys)
+}
 
-	galaxyPositions := expandedSpace.GalaxyPositions()
-	fmt.Println("Galaxy positions:", galaxyPositions)
+// sortedDifferenceSum returns the sum of the absolute differences between all the pairs of the given values, which it
+// sorts: each value is greater than or equal to the i values before it.
+func sortedDifferenceSum(values []int) int {
+	slices.Sort(values)
+	sum, prefixSum := 0, 0
+	for i, value := range values {
+		sum += value*i - prefixSum
+		prefixSum += value
+	}
+	return sum
+}
 
-	var galaxyStepDistanceSum int
-	for i := 0; i < len(galaxyPositions); i++ {
-		for j := i + 1; j < len(galaxyPositions); j++ {
-			galaxyStepDistanceSum += galaxyPositions[i].stepDistanceTo(galaxyPositions[j])
+func UniverseFrom(input string) (*Universe, error) {
+	space, err := grid.Parse(input, func(b byte) (Element, error) {
+		if Element(b) != empty && Element(b) != galaxy {
+			return 0, fmt.Errorf("unexpected %q, expected '.' or '#'", b)
 		}
+		return Element(b), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	fmt.Println("Galaxy step distance sum (part 1): ", galaxyStepDistanceSum)
-
-	fastExpansionUniverse := FastExpansionUniverseFrom(universe)
-	galaxyPositions = fastExpansionUniverse.galaxyPositions
-	galaxyStepDistanceSum = 0
-	for i := 0; i < len(galaxyPositions); i++ {
-		for j := i + 1; j < len(galaxyPositions); j++ {
-			galaxyStepDistanceSum += galaxyPositions[i].stepDistanceTo(galaxyPositions[j])
+	universe := &Universe{width: space.Width(), height: space.Height()}
+	for position, element := range space.All() {
+		if element == galaxy {
+			universe.galaxies = append(universe.galaxies, position)
 		}
 	}
-	fmt.Println("Galaxy step distance sum (part 2): ", galaxyStepDistanceSum)
+	return universe, nil
+}
+
+func init() {
+	registry.Register(2023, 11, registry.Solver[*Universe]{
+		Parse: UniverseFrom,
+		Part1: func(universe *Universe) (any, error) {
+			expanded, err := universe.Expand(2)
+			if err != nil {
+				return nil, err
+			}
+			slog.Debug("expanded universe", "initial", universe, "expanded", expanded)
+			slog.Debug("found galaxies", "positions", expanded.GalaxyPositions())
+			return expanded.DistanceSum(), nil
+		},
+		Part2: func(universe *Universe) (any, error) {
+			expanded, err := universe.Expand(1_000_000)
+			if err != nil {
+				return nil, err
+			}
+			return expanded.DistanceSum(), nil
+		},
+	})
 }
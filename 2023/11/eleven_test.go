@@ -0,0 +1,73 @@
+package day11
+
+import (
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 11)
+}
+
+func TestDistanceSum(t *testing.T) {
+	for factor, expected := range map[int]int{1: 292, 2: 374, 10: 1030, 100: 8410} {
+		if actual := expandedExample(t, factor).DistanceSum(); actual != expected {
+			t.Errorf("factor %d: expected %d, got %d", factor, expected, actual)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	expanded := expandedExample(t, 2)
+	for pair, expected := range map[Pair]int{{4, 8}: 9, {0, 6}: 15, {2, 5}: 17, {7, 8}: 5} {
+		if actual := expanded.Distance(pair.I, pair.J); actual != expected {
+			t.Errorf("galaxies %d and %d: expected %d, got %d", pair.I+1, pair.J+1, expected, actual)
+		}
+	}
+	sum, pairs := 0, 0
+	for _, distance := range expanded.Distances() {
+		sum += distance
+		pairs++
+	}
+	if pairs != 36 || sum != expanded.DistanceSum() {
+		t.Errorf("expected 36 pairs summing to %d, got %d summing to %d", expanded.DistanceSum(), pairs, sum)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	expected := `....#........
+.........#...
+#............
+.............
+.............
+........#....
+.#...........
+............#
+.............
+.............
+.........#...
+#....#.......
+`
+	if actual := expandedExample(t, 2).String(); actual != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+	for _, factor := range []int{0, -1} {
+		if _, err := aoctest.ParseExample(t, UniverseFrom).Expand(factor); err == nil {
+			t.Errorf("expected an error for factor %d", factor)
+		}
+	}
+}
+
+func expandedExample(t *testing.T, factor int) *Universe {
+	t.Helper()
+	expanded, err := aoctest.ParseExample(t, UniverseFrom).Expand(factor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expanded
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 11)
+}
@@ -1,11 +1,12 @@
-package main
+package day12
 
 import (
-	_ "embed"
 	"fmt"
-	"slices"
-	"strconv"
-	"strings"
+	"iter"
+	"log/slog"
+
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
 type State rune
@@ -30,54 +31,30 @@ type ConditionRecord struct {
 	damagedGroupSizes []int
 }
 
-func ConditionRecordFrom(input string) ConditionRecord {
-	fields := strings.Fields(input)
-	states := make([]State, len(fields[0]))
-	for i, char := range fields[0] {
+func ConditionRecordFrom(line parse.Token) (ConditionRecord, error) {
+	fields, err := line.ExpectFields(2)
+	if err != nil {
+		return ConditionRecord{}, err
+	}
+	states := make([]State, len(fields[0].Text))
+	for i, char := range fields[0].Text {
 		states[i] = State(char)
+		if states[i] != Operational && states[i] != Damaged && states[i] != Unknown {
+			return ConditionRecord{}, fields[0].Slice(i, i+1).Errorf("unknown state %q", char)
+		}
 	}
 	var damagedGroupSizes []int
-	for _, damagedGroupSizeStr := range strings.Split(fields[1], ",") {
-		damagedGroupSize, _ := strconv.Atoi(damagedGroupSizeStr)
-		damagedGroupSizes = append(damagedGroupSizes, damagedGroupSize)
-	}
-	return ConditionRecord{states, damagedGroupSizes}
-}
-
-func (record *ConditionRecord) TargetDamagedCount() int {
-	total := 0
-	for _, damagedGroupSize := range record.damagedGroupSizes {
-		total += damagedGroupSize
-	}
-	return total
-}
-
-func (record *ConditionRecord) DamagedCount() int {
-	return record.count(Damaged)
-}
-
-func (record *ConditionRecord) DamagedToFindCount() int {
-	return record.TargetDamagedCount() - record.DamagedCount()
-}
-
-func (record *ConditionRecord) count(state State) int {
-	count := 0
-	for _, s := range record.states {
-		if s == state {
-			count++
+	for _, damagedGroupSizeToken := range fields[1].Split(",") {
+		damagedGroupSize, err := damagedGroupSizeToken.Int()
+		if err != nil {
+			return ConditionRecord{}, err
 		}
-	}
-	return count
-}
-
-func (record *ConditionRecord) UnknownIndices() []int {
-	indices := make([]int, 0)
-	for i, state := range record.states {
-		if state == Unknown {
-			indices = append(indices, i)
+		if damagedGroupSize < 1 {
+			return ConditionRecord{}, damagedGroupSizeToken.Errorf("expected a positive group size, got %d", damagedGroupSize)
 		}
+		damagedGroupSizes = append(damagedGroupSizes, damagedGroupSize)
 	}
-	return indices
+	return ConditionRecord{states, damagedGroupSizes}, nil
 }
 
 func (record *ConditionRecord) IsValid() bool {
@@ -112,19 +89,17 @@ func (record *ConditionRecord) DamagedGroups() []DamagedGroup {
 	return damagedGroups
 }
 
-func (record *ConditionRecord) Unfold() ConditionRecord {
-	unfoldedStates := make([]State, 0, 5*len(record.states)+4)
-	unfoldedDamagedGroupSizes := make([]int, 0, 5*len(record.damagedGroupSizes))
-	for repeat := 0; repeat < 5; repeat++ {
-		for _, state := range record.states {
-			unfoldedStates = append(unfoldedStates, state)
-		}
-		if repeat < 4 {
+// Unfold returns the record repeated the given number of times, the copies of the states being separated by an unknown
+// state.
+func (record *ConditionRecord) Unfold(factor int) ConditionRecord {
+	unfoldedStates := make([]State, 0, factor*(len(record.states)+1))
+	unfoldedDamagedGroupSizes := make([]int, 0, factor*len(record.damagedGroupSizes))
+	for repeat := range factor {
+		if repeat > 0 {
 			unfoldedStates = append(unfoldedStates, Unknown)
 		}
-		for _, damagedGroupSize := range record.damagedGroupSizes {
-			unfoldedDamagedGroupSizes = append(unfoldedDamagedGroupSizes, damagedGroupSize)
-		}
+		unfoldedStates = append(unfoldedStates, record.states...)
+		unfoldedDamagedGroupSizes = append(unfoldedDamagedGroupSizes, record.damagedGroupSizes...)
 	}
 	return ConditionRecord{unfoldedStates, unfoldedDamagedGroupSizes}
 }
@@ -135,194 +110,155 @@ func (record *ConditionRecord) String() string {
 
 type ConditionRecords []ConditionRecord
 
-func ConditionRecordsFrom(input string) ConditionRecords {
-	lines := strings.Split(input, "\n")
+func ConditionRecordsFrom(input string) (ConditionRecords, error) {
+	lines := parse.Lines(input)
 	conditionRecords := make(ConditionRecords, len(lines))
 	for i, line := range lines {
-		conditionRecords[i] = ConditionRecordFrom(line)
+		var err error
+		if conditionRecords[i], err = ConditionRecordFrom(line); err != nil {
+			return nil, err
+		}
 	}
-	return conditionRecords
+	return conditionRecords, nil
 }
 
-func (records ConditionRecords) Unfold() ConditionRecords {
+// Unfold returns the records unfolded the given number of times.
+func (records ConditionRecords) Unfold(factor int) ConditionRecords {
 	unfoldedConditionRecords := make(ConditionRecords, len(records))
 	for i, record := range records {
-		unfoldedConditionRecords[i] = record.Unfold()
+		unfoldedConditionRecords[i] = record.Unfold(factor)
 	}
 	return unfoldedConditionRecords
 }
 
-type NaiveFiller struct{}
-
-func (filler *NaiveFiller) Fill(record *ConditionRecord) [][]State {
-	unknownIndices := record.UnknownIndices()
-	if len(unknownIndices) == 0 {
-		return [][]State{record.states}
-	}
-	solutions := make([][]State, 0)
-	filler.combinations(record.DamagedToFindCount(), unknownIndices, func(combination []int) {
-		candidate := slices.Clone(record.states)
-		for _, unknownIndex := range unknownIndices {
-			if slices.Contains(combination, unknownIndex) {
-				candidate[unknownIndex] = Damaged
-			} else {
-				candidate[unknownIndex] = Operational
-			}
-		}
-		candidateRecord := ConditionRecord{candidate, record.damagedGroupSizes}
-		if candidateRecord.IsValid() {
-			solutions = append(solutions, candidate)
-		}
-	})
-	return solutions
+// CountArrangements returns the number of ways to replace the unknown states by operational or damaged ones so that
+// the damaged groups match their recorded sizes.
+func (record *ConditionRecord) CountArrangements() int64 {
+	return record.arrangementCounts().at(0, 0)
 }
 
-// See https://www.baeldung.com/cs/generate-k-combinations, lexicographic generation.
-// Watch out, c indices start at 0 here.
-func (filler *NaiveFiller) combinations(k int, elements []int, visitCombination func([]int)) {
-
-	// Initialization
-	c := make([]int, k+2)
-	for i := 0; i < k; i++ {
-		c[i] = i
-	}
-	c[k] = len(elements)
-	c[k+1] = 0
-
-	for {
-		// Visit combination
-		combination := make([]int, k)
-		for i, ci := range c[0:k] {
-			combination[i] = elements[ci]
-		}
-		visitCombination(combination)
-
-		// Find j
-		j := 0
-		for c[j]+1 == c[j+1] {
-			c[j] = j
-			j++
-		}
-		// Done?
-		if j < k {
-			// Increment
-			c[j] = c[j] + 1
-		} else {
-			// End
-			break
+// Arrangements returns an iterator over the ways to replace the unknown states so that the damaged groups match their
+// recorded sizes, unknown states being tried as operational first. Arrangements are computed lazily, in a buffer reused
+// between iterations: clone it to keep it.
+func (record *ConditionRecord) Arrangements() iter.Seq[[]State] {
+	return func(yield func([]State) bool) {
+		counts := record.arrangementCounts()
+		arrangement := make([]State, len(record.states))
+		// fill fills the arrangement from the given position with the groups from the given index, and returns false
+		// once the iteration is stopped; it only explores the branches leading to an arrangement
+		var fill func(position, group int) bool
+		fill = func(position, group int) bool {
+			if counts.at(position, group) == 0 {
+				return true
+			}
+			if position == len(arrangement) {
+				return yield(arrangement)
+			}
+			if record.states[position] != Damaged {
+				arrangement[position] = Operational
+				if !fill(position+1, group) {
+					return false
+				}
+			}
+			next, fits := counts.fitsGroup(position, group)
+			if !fits {
+				return true
+			}
+			end := position + record.damagedGroupSizes[group]
+			for i := position; i < end; i++ {
+				arrangement[i] = Damaged
+			}
+			if end < len(arrangement) {
+				arrangement[end] = Operational
+			}
+			return fill(next, group+1)
 		}
+		fill(0, 0)
 	}
 }
 
-type LessNaiveFillCounter struct {
-	cache map[string]int64
-}
-
-func NewLessNaiveFillCounter() *LessNaiveFillCounter {
-	return &LessNaiveFillCounter{cache: make(map[string]int64)}
+// arrangementCounts holds the number of arrangements of the states from each position with the damaged groups from
+// each index.
+type arrangementCounts struct {
+	record *ConditionRecord
+	// damageable is, for each position, the number of consecutive states from there which are not operational
+	damageable []int
+	counts     []int64
 }
 
-func (filler *LessNaiveFillCounter) CountFills(record *ConditionRecord) int64 {
-
-	if cachedCount, ok := filler.cache[record.String()]; ok {
-		return cachedCount
+// arrangementCounts counts the arrangements of each suffix of the states with each suffix of the damaged groups,
+// from the end: the state at a position is either operational, or damaged and starts the next group.
+func (record *ConditionRecord) arrangementCounts() arrangementCounts {
+	states, groups := len(record.states), len(record.damagedGroupSizes)
+	c := arrangementCounts{
+		record:     record,
+		damageable: make([]int, states+1),
+		counts:     make([]int64, (states+1)*(groups+1)),
 	}
-
-	groupSizes := record.damagedGroupSizes
-	states := record.states
-
-	if len(groupSizes) == 0 {
-		candidate := slices.Clone(states)
-		for i, s := range candidate {
-			if s == Damaged {
-				return 0
-			}
-			candidate[i] = Operational
+	for position := states - 1; position >= 0; position-- {
+		if record.states[position] != Operational {
+			c.damageable[position] = c.damageable[position+1] + 1
 		}
-		return 1
-	}
-
-	if filler.requiredSpace(groupSizes) > len(states) {
-		return 0
 	}
-
-	candidate := make([]State, len(states))
-	groupSize := groupSizes[0]
-	count := int64(0)
-
-outer:
-	for groupStartIndex := 0; groupStartIndex < len(states)-groupSize+1; groupStartIndex++ {
-		copy(candidate, states)
-		for beforeStartIndex := 0; beforeStartIndex < groupStartIndex; beforeStartIndex++ {
-			if candidate[beforeStartIndex] == Damaged {
-				continue outer
-			}
-			candidate[beforeStartIndex] = Operational
-		}
-		for indexInGroup := 0; indexInGroup < groupSize; indexInGroup++ {
-			if candidate[groupStartIndex+indexInGroup] == Operational {
-				continue outer
+	c.counts[c.index(states, groups)] = 1
+	for position := states - 1; position >= 0; position-- {
+		for group := groups; group >= 0; group-- {
+			count := int64(0)
+			if record.states[position] != Damaged {
+				count += c.at(position+1, group)
 			}
-			candidate[groupStartIndex+indexInGroup] = Damaged
-		}
-		groupSeparatorIndex := groupStartIndex + groupSize
-		if len(candidate) > groupSeparatorIndex {
-			if candidate[groupSeparatorIndex] == Damaged {
-				continue
+			if next, fits := c.fitsGroup(position, group); fits {
+				count += c.at(next, group+1)
 			}
-			candidate[groupSeparatorIndex] = Operational
-		}
-		if len(candidate) > groupSeparatorIndex+1 {
-			subRecord := &ConditionRecord{candidate[groupSeparatorIndex+1:], groupSizes[1:]}
-			count += filler.CountFills(subRecord)
-		} else if len(groupSizes) == 1 {
-			count++
+			c.counts[c.index(position, group)] = count
 		}
 	}
-
-	filler.cache[record.String()] = count
-	return count
+	return c
 }
 
-func (filler *LessNaiveFillCounter) requiredSpace(groupSizes []int) int {
-	count := 0
-	for _, groupSize := range groupSizes {
-		count += groupSize
-	}
-	count += len(groupSizes) - 1
-	return count
+func (c arrangementCounts) index(position, group int) int {
+	return position*(len(c.record.damagedGroupSizes)+1) + group
 }
 
-//go:embed input.txt
-var input string
+func (c arrangementCounts) at(position, group int) int64 {
+	return c.counts[c.index(position, group)]
+}
 
-func main() {
-	conditionsRecords := ConditionRecordsFrom(input)
-	filler := NaiveFiller{}
-	sum := int64(0)
-	for _, record := range conditionsRecords {
-		solutions := filler.Fill(&record)
-		fmt.Printf("%v solutions for %q\n", len(solutions), record.states)
-		sum += int64(len(solutions))
+// fitsGroup tells whether the damaged group of given index can start at the given position, i.e. whether its states
+// can be damaged and the state right after it, if any, can be operational, and returns the position following it.
+func (c arrangementCounts) fitsGroup(position, group int) (int, bool) {
+	if group == len(c.record.damagedGroupSizes) {
+		return 0, false
 	}
-	fmt.Println("Total solution count (part 1, naive filler): ", sum)
-
-	fillCounter := NewLessNaiveFillCounter()
-	sum = 0
-	for _, record := range conditionsRecords {
-		count := fillCounter.CountFills(&record)
-		fmt.Printf("%v solutions for %q\n", count, record.states)
-		sum += count
+	end := position + c.record.damagedGroupSizes[group]
+	if c.damageable[position] < end-position {
+		return 0, false
+	}
+	if end == len(c.record.states) {
+		return end, true
 	}
-	fmt.Println("Total solution count (part 1, less naive fill counter): ", sum)
+	return end + 1, c.record.states[end] != Damaged
+}
 
-	sum = 0
-	conditionsRecords = conditionsRecords.Unfold()
-	for _, record := range conditionsRecords {
-		count := fillCounter.CountFills(&record)
-		fmt.Printf("%v solutions for %q\n", count, record.states)
+// sumArrangements returns the sum of the numbers of arrangements of the given records.
+func sumArrangements(records ConditionRecords) int64 {
+	sum := int64(0)
+	for _, record := range records {
+		count := record.CountArrangements()
+		slog.Debug("counted arrangements", "record", &record, "count", count)
 		sum += count
 	}
-	fmt.Println("Total solution count (part 2, less naive fill counter): ", sum)
+	return sum
+}
 
+func init() {
+	registry.Register(2023, 12, registry.Solver[ConditionRecords]{
+		Parse: ConditionRecordsFrom,
+		Part1: func(conditionsRecords ConditionRecords) (any, error) {
+			return sumArrangements(conditionsRecords), nil
+		},
+		Part2: func(conditionsRecords ConditionRecords) (any, error) {
+			return sumArrangements(conditionsRecords.Unfold(5)), nil
+		},
+	})
 }
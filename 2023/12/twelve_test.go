@@ -0,0 +1,99 @@
+package day12
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/parse"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 12)
+}
+
+func TestCountArrangements(t *testing.T) {
+	records := aoctest.ParseExample(t, ConditionRecordsFrom)
+	for factor, expected := range map[int][]int64{
+		1: {1, 4, 1, 1, 4, 10},
+		5: {1, 16384, 1, 16, 2500, 506250},
+	} {
+		for i, record := range records.Unfold(factor) {
+			if actual := record.CountArrangements(); actual != expected[i] {
+				t.Errorf("%v unfolded %d times: expected %d arrangements, got %d", &records[i], factor, expected[i], actual)
+			}
+		}
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	record, err := ConditionRecordFrom(parse.Token{Text: ".# 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for factor, expected := range map[int]string{
+		0: " []",
+		1: ".# [1]",
+		5: ".#?.#?.#?.#?.# [1 1 1 1 1]",
+	} {
+		unfolded := record.Unfold(factor)
+		if actual := unfolded.String(); actual != expected {
+			t.Errorf("factor %d: expected %q, got %q", factor, expected, actual)
+		}
+	}
+}
+
+func TestConditionRecordFromInvalidGroupSize(t *testing.T) {
+	for _, line := range []string{"??? -1", "??? 1,0"} {
+		_, err := ConditionRecordFrom(parse.Token{Text: line, Line: 1, Column: 1})
+		var parseErr *parse.Error
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: expected a parse error, got %v", line, err)
+		}
+	}
+}
+
+func TestArrangements(t *testing.T) {
+	records := aoctest.ParseExample(t, ConditionRecordsFrom)
+	for _, record := range records {
+		var arrangements []string
+		for arrangement := range record.Arrangements() {
+			candidate := ConditionRecord{arrangement, record.damagedGroupSizes}
+			if !candidate.IsValid() {
+				t.Errorf("%v: invalid arrangement %s", &record, string(arrangement))
+			}
+			for i, state := range record.states {
+				if state != Unknown && arrangement[i] != state {
+					t.Errorf("%v: arrangement %s changes known state %d", &record, string(arrangement), i)
+				}
+			}
+			arrangements = append(arrangements, string(arrangement))
+		}
+		if int64(len(arrangements)) != record.CountArrangements() {
+			t.Errorf("%v: expected %d arrangements, got %v", &record, record.CountArrangements(), arrangements)
+		}
+		slices.Sort(arrangements)
+		if len(slices.Compact(arrangements)) != len(arrangements) {
+			t.Errorf("%v: duplicate arrangements in %v", &record, arrangements)
+		}
+	}
+}
+
+func TestArrangementsStop(t *testing.T) {
+	expected := []string{".###....##.#", ".###...##..#"}
+	var actual []string
+	for arrangement := range aoctest.ParseExample(t, ConditionRecordsFrom)[5].Arrangements() {
+		actual = append(actual, string(arrangement))
+		if len(actual) == len(expected) {
+			break
+		}
+	}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 12)
+}
@@ -1,206 +1,151 @@
-package main
+package day17
 
 import (
-	_ "embed"
-	"fmt"
-	"math"
-	"slices"
-	"strconv"
-	"strings"
+	"errors"
+	"log/slog"
+
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
+	"github.com/super7ramp/aoc/search"
 )
 
-type Position struct {
-	column int
-	row    int
+// Crucible describes how many blocks a crucible can move in a straight line: at least minStraight blocks before
+// turning or stopping, at most maxStraight blocks before having to turn.
+type Crucible struct {
+	minStraight int
+	maxStraight int
 }
 
-func Pos(column, row int) Position {
-	return Position{column, row}
-}
+// ErrNoPath is returned when a crucible cannot reach the bottom-right block.
+var ErrNoPath = errors.New("no path to the bottom-right block")
 
-func PositionsAligned(a, b, c, d, e Position) bool {
-	rowDifferences := abs(a.row-b.row) + abs(b.row-c.row) + abs(c.row-d.row) + abs(d.row-e.row)
-	columnDifferences := abs(a.column-b.column) + abs(b.column-c.column) + abs(c.column-d.column) + abs(d.column-e.column)
-	return rowDifferences == 0 || columnDifferences == 0
-}
+var (
+	RegularCrucible = Crucible{minStraight: 1, maxStraight: 3}
+	UltraCrucible   = Crucible{minStraight: 4, maxStraight: 10}
+)
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+// crucibleState is the state of a crucible on the map. The position alone is not enough: where the crucible can go
+// next depends on its direction and on the number of blocks it has moved straight in this direction.
+type crucibleState struct {
+	pos       grid.Pos
+	direction grid.Vec
+	straight  int
 }
 
-type HeatLossMap [][]int
-
-func NewPuzzleMap(input string) HeatLossMap {
-	rows := strings.Split(input, "\n")
-	rowCount := len(rows)
-	columnCount := len(rows[0])
-	heatLosses := make([][]int, rowCount)
+type HeatLossMap struct {
+	heatLosses *grid.Grid[int]
+}
 
-	for rowIndex, row := range rows {
-		heatLosses[rowIndex] = make([]int, columnCount)
-		for columnIndex, char := range row {
-			heatLoss, _ := strconv.Atoi(string(char))
-			heatLosses[rowIndex][columnIndex] = heatLoss
-		}
+func NewPuzzleMap(input string) (HeatLossMap, error) {
+	heatLosses, err := grid.Parse(input, grid.Digit)
+	if err != nil {
+		return HeatLossMap{}, err
 	}
-
-	return heatLosses
+	return HeatLossMap{heatLosses}, nil
 }
 
-func (heatLossMap *HeatLossMap) NeighborsOf(position Position) []Position {
-	neighbors := make([]Position, 0)
-	if position.row > 0 {
-		neighbors = append(neighbors, Pos(position.column, position.row-1))
-	}
-	if position.row < heatLossMap.RowCount()-1 {
-		neighbors = append(neighbors, Pos(position.column, position.row+1))
-	}
-	if position.column > 0 {
-		neighbors = append(neighbors, Pos(position.column-1, position.row))
-	}
-	if position.column < heatLossMap.ColumnCount()-1 {
-		neighbors = append(neighbors, Pos(position.column+1, position.row))
-	}
-	return neighbors
+func (heatLossMap *HeatLossMap) Contains(position grid.Pos) bool {
+	return heatLossMap.heatLosses.Contains(position)
 }
 
-func (heatLossMap *HeatLossMap) Contains(position Position) bool {
-	return position.row < heatLossMap.RowCount() && position.column < heatLossMap.ColumnCount()
+func (heatLossMap *HeatLossMap) HeatLossAt(position grid.Pos) int {
+	return heatLossMap.heatLosses.At(position)
 }
 
 func (heatLossMap *HeatLossMap) RowCount() int {
-	return len(*heatLossMap)
+	return heatLossMap.heatLosses.Height()
 }
 
 func (heatLossMap *HeatLossMap) ColumnCount() int {
-	return len((*heatLossMap)[0])
-}
-
-type NodeStatus struct {
-	cumulatedHeatLoss int
-	from              Position
-	visited           bool
-}
-
-type DijsktraBasedShortestPathFinder struct {
-	heatLossMap HeatLossMap
-	statuses    map[Position]*NodeStatus
-	current     Position
+	return heatLossMap.heatLosses.Width()
 }
 
-func NewDijsktraBasedShortestPathFinder(graph HeatLossMap) *DijsktraBasedShortestPathFinder {
-	statuses := make(map[Position]*NodeStatus)
-	current := Position{}
-	return &DijsktraBasedShortestPathFinder{graph, statuses, current}
-}
-
-func (finder *DijsktraBasedShortestPathFinder) PathWithMinimalHeatLoss(from, to Position) (int, []Position) {
-	defer clear(finder.statuses)
-	*finder.status(from) = NodeStatus{cumulatedHeatLoss: 0, visited: true, from: from}
-	finder.current = from
-
-	for finder.current != to {
-		for _, neighbor := range finder.nonVisitedNeighbors() {
-			finder.updateNeighborCumulatedHeatLoss(neighbor)
-		}
-		newCurrent := finder.nonVisitedPositionWithMinimalCumulatedHeatLoss()
-		finder.markVisited(newCurrent)
-		finder.current = newCurrent
+// PathWithMinimalHeatLoss returns the minimal heat loss the given crucible incurs going from the top-left block to the
+// bottom-right block, along with the positions of the corresponding path, start excluded. It returns ErrNoPath if the
+// crucible cannot reach the bottom-right block.
+func (heatLossMap *HeatLossMap) PathWithMinimalHeatLoss(crucible Crucible) (int, []grid.Pos, error) {
+	from := grid.Pos{}
+	to := grid.Pos{X: heatLossMap.ColumnCount() - 1, Y: heatLossMap.RowCount() - 1}
+	isGoal := func(state crucibleState) bool {
+		return state.pos == to && state.straight >= crucible.minStraight
 	}
-
-	path := make([]Position, 0)
-	for current := to; current != from; current = finder.previousOf(current) {
-		path = append(path, current)
+	neighbors := func(state crucibleState) []search.Edge[crucibleState] {
+		return heatLossMap.nextStates(state, crucible)
 	}
-	slices.Reverse(path)
-
-	return finder.status(to).cumulatedHeatLoss, path
-}
-
-func (finder *DijsktraBasedShortestPathFinder) updateNeighborCumulatedHeatLoss(neighbor Position) {
-	heatLossFromCurrent := finder.status(finder.current).cumulatedHeatLoss + finder.heatLossMap[neighbor.row][neighbor.column]
-	neighborStatus := finder.status(neighbor)
-	if heatLossFromCurrent < neighborStatus.cumulatedHeatLoss {
-		neighborStatus.cumulatedHeatLoss = heatLossFromCurrent
-		neighborStatus.from = finder.current
+	heuristic := func(state crucibleState) int {
+		// every block incurs a heat loss of at least 1
+		return state.pos.ManhattanDistance(to)
 	}
-}
 
-func (finder *DijsktraBasedShortestPathFinder) nonVisitedNeighbors() []Position {
-	neighbors := finder.heatLossMap.NeighborsOf(finder.current)
-	slices.DeleteFunc(neighbors, func(position Position) bool {
-		return finder.hasVisited(position) || finder.lastFourPositionsAlignedWith(position)
-	})
-	return neighbors
-}
-
-func (finder *DijsktraBasedShortestPathFinder) nonVisitedPositionWithMinimalCumulatedHeatLoss() Position {
-	minimalHeatLoss := math.MaxInt
-	var position Position
-	for candidate, status := range finder.statuses {
-		if !status.visited && status.cumulatedHeatLoss < minimalHeatLoss {
-			minimalHeatLoss = status.cumulatedHeatLoss
-			position = candidate
+	path, found := search.AStar(crucibleState{pos: from}, isGoal, neighbors, heuristic)
+	if !found {
+		return 0, nil, ErrNoPath
+	}
+	positions := make([]grid.Pos, 0, len(path.States)-1)
+	for _, state := range path.States[1:] {
+		positions = append(positions, state.pos)
+	}
+	return path.Cost, positions, nil
+}
+
+func (heatLossMap *HeatLossMap) nextStates(state crucibleState, crucible Crucible) []search.Edge[crucibleState] {
+	var edges []search.Edge[crucibleState]
+	for _, direction := range grid.Directions4 {
+		var next crucibleState
+		switch {
+		case direction == state.direction.Reverse():
+			// crucibles cannot reverse direction
+			continue
+		case direction == state.direction:
+			if state.straight >= crucible.maxStraight {
+				continue
+			}
+			next = crucibleState{state.pos.Add(direction), direction, state.straight + 1}
+		default:
+			isStart := state.direction == grid.Vec{}
+			if !isStart && state.straight < crucible.minStraight {
+				continue
+			}
+			next = crucibleState{state.pos.Add(direction), direction, 1}
+		}
+		if heatLossMap.Contains(next.pos) {
+			edges = append(edges, search.Edge[crucibleState]{To: next, Cost: heatLossMap.HeatLossAt(next.pos)})
 		}
 	}
-	return position
-}
-
-func (finder *DijsktraBasedShortestPathFinder) hasVisited(position Position) bool {
-	return finder.status(position).visited
+	return edges
 }
 
-func (finder *DijsktraBasedShortestPathFinder) markVisited(position Position) {
-	finder.status(position).visited = true
-}
-
-func (finder *DijsktraBasedShortestPathFinder) status(position Position) *NodeStatus {
-	status, isPresent := finder.statuses[position]
-	if !isPresent {
-		status = &NodeStatus{cumulatedHeatLoss: math.MaxInt}
-		finder.statuses[position] = status
+// RenderPath renders the map with the given path drawn on it: each block of the path is marked with the last digit
+// of its index in the path.
+func (heatLossMap *HeatLossMap) RenderPath(path []grid.Pos) string {
+	rendering := grid.New[byte](heatLossMap.ColumnCount(), heatLossMap.RowCount())
+	for pos := range rendering.Positions() {
+		rendering.Set(pos, '.')
 	}
-	return status
-}
-
-func (finder *DijsktraBasedShortestPathFinder) lastFourPositionsAlignedWith(position Position) bool {
-	current := finder.current
-	previous := finder.previousOf(current)
-	beforePrevious := finder.previousOf(previous)
-	beforeBeforePrevious := finder.previousOf(beforePrevious)
-	if current == previous || previous == beforePrevious || beforePrevious == beforeBeforePrevious {
-		// previous of start is itself, don't count it twice
-		return false
+	for i, pos := range path {
+		rendering.Set(pos, byte('0'+i%10))
 	}
-	return PositionsAligned(position, current, previous, beforePrevious, beforeBeforePrevious)
+	return rendering.String()
 }
 
-func (finder *DijsktraBasedShortestPathFinder) previousOf(position Position) Position {
-	return finder.status(position).from
+func init() {
+	registry.Register(2023, 17, registry.Solver[HeatLossMap]{
+		Parse: NewPuzzleMap,
+		Part1: func(puzzleMap HeatLossMap) (any, error) { return minimalHeatLoss(puzzleMap, RegularCrucible) },
+		Part2: func(puzzleMap HeatLossMap) (any, error) { return minimalHeatLoss(puzzleMap, UltraCrucible) },
+	})
 }
 
-//go:embed input-example.txt
-var input string
-
-func main() {
-	puzzleMap := NewPuzzleMap(input)
-	dijsktra := NewDijsktraBasedShortestPathFinder(puzzleMap)
-	from, to := Pos(0, 0), Pos(puzzleMap.ColumnCount()-1, puzzleMap.RowCount()-1)
-	heatLoss, path := dijsktra.PathWithMinimalHeatLoss(from, to)
-	fmt.Println("Heat loss: ", heatLoss)
-	fmt.Println("Path: ", path)
-	for i := 0; i < puzzleMap.RowCount(); i++ {
-		for j := 0; j < puzzleMap.ColumnCount(); j++ {
-			pos := Pos(i, j)
-			if slices.Contains(path, pos) {
-				fmt.Print(slices.Index(path, pos) % 10)
-			} else {
-				fmt.Print(".")
-			}
-		}
-		fmt.Print("\n")
+// minimalHeatLoss returns the minimal heat loss the given crucible incurs on the given map, or the error preventing it
+// from reaching the bottom-right block.
+func minimalHeatLoss(heatLossMap HeatLossMap, crucible Crucible) (int, error) {
+	heatLoss, path, err := heatLossMap.PathWithMinimalHeatLoss(crucible)
+	if err != nil {
+		return 0, err
+	}
+	if report.Enabled(slog.LevelDebug) {
+		slog.Debug("found path", "heatLoss", heatLoss, "path", heatLossMap.RenderPath(path))
 	}
+	return heatLoss, nil
 }
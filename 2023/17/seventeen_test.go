@@ -0,0 +1,29 @@
+package day17
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2023, 17)
+}
+
+func TestPathWithMinimalHeatLossUnreachable(t *testing.T) {
+	heatLossMap, err := NewPuzzleMap("111\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := heatLossMap.PathWithMinimalHeatLoss(UltraCrucible); !errors.Is(err, ErrNoPath) {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+	if heatLoss, _, err := heatLossMap.PathWithMinimalHeatLoss(RegularCrucible); err != nil || heatLoss != 2 {
+		t.Errorf("expected 2, got %d, %v", heatLoss, err)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2023, 17)
+}
@@ -1,55 +1,59 @@
-package main
+package day01
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
-	"strconv"
-	"strings"
-)
-
-//go:embed input.txt
-var input string
 
-func main() {
-	column1, column2 := columns()
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+)
 
-	// Part 1
-	slices.Sort(column1)
-	slices.Sort(column2)
-	fmt.Println("Sorted column 1:", column1)
-	fmt.Println("Sorted column 2:", column2)
-	differenceSum := 0
-	for i := range column1 {
-		differenceSum += abs(column2[i] - column1[i])
-	}
-	fmt.Println("Sum of differences:", differenceSum)
+type Columns struct {
+	column1, column2 []int
+}
 
-	// Part 2
-	similarityScore := 0
-	for _, number := range column1 {
-		similarityScore += number * countNumber(number, column2)
-	}
-	fmt.Println("Similarity score:", similarityScore)
+func init() {
+	registry.Register(2024, 1, registry.Solver[Columns]{
+		Parse: columns,
+		Part1: func(c Columns) (any, error) {
+			column1, column2 := slices.Clone(c.column1), slices.Clone(c.column2)
+			slices.Sort(column1)
+			slices.Sort(column2)
+			slog.Debug("sorted columns", "column1", column1, "column2", column2)
+			differenceSum := 0
+			for i := range column1 {
+				differenceSum += aocmath.Abs(column2[i] - column1[i])
+			}
+			return differenceSum, nil
+		},
+		Part2: func(c Columns) (any, error) {
+			similarityScore := 0
+			for _, number := range c.column1 {
+				similarityScore += number * countNumber(number, c.column2)
+			}
+			return similarityScore, nil
+		},
+	})
 }
 
-func columns() ([]int, []int) {
-	lines := strings.Split(input, "\n")
+func columns(input string) (Columns, error) {
+	lines := parse.Lines(input)
 	column1 := make([]int, len(lines))
 	column2 := make([]int, len(lines))
 	for i, line := range lines {
-		parts := strings.Split(line, "   ")
-		column1[i], _ = strconv.Atoi(parts[0])
-		column2[i], _ = strconv.Atoi(parts[1])
-	}
-	return column1, column2
-}
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
+		parts, err := line.ExpectFields(2)
+		if err != nil {
+			return Columns{}, err
+		}
+		if column1[i], err = parts[0].Int(); err != nil {
+			return Columns{}, err
+		}
+		if column2[i], err = parts[1].Int(); err != nil {
+			return Columns{}, err
+		}
 	}
-	return n
+	return Columns{column1, column2}, nil
 }
 
 func countNumber(number int, numbers []int) int {
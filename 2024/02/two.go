@@ -1,49 +1,74 @@
-package main
+package day02
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
-	"strconv"
-	"strings"
-)
 
-//go:embed input.txt
-var input string
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+)
 
-func main() {
-	reports := strings.Split(input, "\n")
+// Report is a list of levels.
+type Report []int
 
-	part1Reports := slices.Clone(reports)
-	part1Reports = slices.DeleteFunc(part1Reports, isReportUnsafeWithoutTolerance)
-	fmt.Printf("Part 1: %v safe report(s): %q\n", len(part1Reports), part1Reports)
+func init() {
+	registry.Register(2024, 2, registry.Solver[[]Report]{
+		Parse: parseReports,
+		Part1: func(reports []Report) (any, error) {
+			part1Reports := slices.Clone(reports)
+			part1Reports = slices.DeleteFunc(part1Reports, isReportUnsafeWithoutTolerance)
+			slog.Debug("found safe reports", "count", len(part1Reports), "reports", part1Reports)
+			return len(part1Reports), nil
+		},
+		Part2: func(reports []Report) (any, error) {
+			part2Reports := slices.Clone(reports)
+			part2Reports = slices.DeleteFunc(part2Reports, isReportUnsafeWithToleranceOfOne)
+			slog.Debug("found safe reports", "count", len(part2Reports), "reports", part2Reports)
+			return len(part2Reports), nil
+		},
+	})
+}
 
-	part2Reports := slices.Clone(reports)
-	part2Reports = slices.DeleteFunc(part2Reports, isReportUnsafeWithToleranceOfOne)
-	fmt.Printf("Part 2: %v safe report(s): %q\n", len(part2Reports), part2Reports)
+func parseReports(input string) ([]Report, error) {
+	lines := parse.Lines(input)
+	reports := make([]Report, len(lines))
+	for i, line := range lines {
+		levels, err := line.Ints()
+		if err != nil {
+			return nil, err
+		}
+		if len(levels) == 0 {
+			return nil, line.Errorf("empty report")
+		}
+		reports[i] = levels
+	}
+	return reports, nil
 }
 
-func isReportUnsafeWithoutTolerance(report string) bool {
+func isReportUnsafeWithoutTolerance(report Report) bool {
 	return !isReportSafe(report, 0)
 }
 
-func isReportUnsafeWithToleranceOfOne(report string) bool {
+func isReportUnsafeWithToleranceOfOne(report Report) bool {
 	return !isReportSafe(report, 1)
 }
 
-func isReportSafe(report string, tolerance int) bool {
-	levels := strings.Split(report, " ")
+func isReportSafe(levels Report, tolerance int) bool {
+	if len(levels) < 2 {
+		return true
+	}
 	safe := true
 	currentTolerance := tolerance
 
-	firstLevel, _ := strconv.Atoi(levels[0])
-	secondLevel, _ := strconv.Atoi(levels[1])
+	firstLevel := levels[0]
+	secondLevel := levels[1]
 	safe = isDiffSafe(firstLevel, secondLevel)
 
 	increasing := secondLevel > firstLevel
 	previousLevel := secondLevel
 	for i := 2; i < len(levels) && safe; i++ {
-		currentLevel, _ := strconv.Atoi(levels[i])
+		currentLevel := levels[i]
 		safeDiff := isDiffSafe(previousLevel, currentLevel)
 		keepsIncreasing := increasing && currentLevel > previousLevel
 		keepsDecreasing := !increasing && currentLevel < previousLevel
@@ -59,8 +84,8 @@ func isReportSafe(report string, tolerance int) bool {
 	}
 
 	if !safe && tolerance > 0 {
-		reportWithoutFirstLevel := report[len(levels[0])+1:]
-		reportWithoutSecondLevel := report[:len(levels[0])+1] + report[len(levels[0])+1+len(levels[1])+1:]
+		reportWithoutFirstLevel := levels[1:]
+		reportWithoutSecondLevel := append(Report{levels[0]}, levels[2:]...)
 		safe = isReportSafe(reportWithoutFirstLevel, tolerance-1) || isReportSafe(reportWithoutSecondLevel, tolerance-1)
 	}
 
@@ -68,13 +93,6 @@ func isReportSafe(report string, tolerance int) bool {
 }
 
 func isDiffSafe(level1, level2 int) bool {
-	diff := abs(level2 - level1)
+	diff := aocmath.Abs(level2 - level1)
 	return diff > 0 && diff <= 3
 }
-
-func abs(n int) int {
-	if n < 0 {
-		return -n
-	}
-	return n
-}
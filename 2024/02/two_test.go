@@ -0,0 +1,15 @@
+package day02
+
+import (
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2024, 2)
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2024, 2)
+}
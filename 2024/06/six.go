@@ -1,10 +1,11 @@
-package main
+package day06
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
-	"strings"
+
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
 )
 
 const (
@@ -32,57 +33,30 @@ func (d Direction) next() Direction {
 	panic("Invalid direction")
 }
 
-type Pos struct {
-	x, y int
-}
-
-func (p *Pos) Up() Pos {
-	return Pos{p.x, p.y - 1}
-}
-
-func (p *Pos) Down() Pos {
-	return Pos{p.x, p.y + 1}
-}
-
-func (p *Pos) Left() Pos {
-	return Pos{p.x - 1, p.y}
-}
-
-func (p *Pos) Right() Pos {
-	return Pos{p.x + 1, p.y}
-}
-
 type PatrolMap struct {
-	tiles [][]byte
+	tiles *grid.Grid[byte]
 }
 
-func PatrolMapFrom(bytes []byte) *PatrolMap {
-	tiles := make([][]byte, 0)
-	currentRow := make([]byte, 0)
-	for _, b := range bytes {
-		if b == '\n' {
-			tiles = append(tiles, slices.Clone(currentRow))
-			currentRow = slices.Delete(currentRow, 0, len(currentRow))
-		} else {
-			currentRow = append(currentRow, b)
-		}
+func PatrolMapFrom(input string) (*PatrolMap, error) {
+	tiles, err := grid.Parse(input, grid.OneOf(string([]byte{Empty, Obstacle, GuardGoingUp, GuardGoingRight,
+		GuardGoingDown, GuardGoingLeft})))
+	if err != nil {
+		return nil, err
 	}
-	return &PatrolMap{tiles}
+	return &PatrolMap{tiles}, nil
 }
 
-func (m *PatrolMap) VisitGuardPositions() []Pos {
-	visited := make([]Pos, 0)
+func (m *PatrolMap) VisitGuardPositions() []grid.Pos {
+	visited := make([]grid.Pos, 0)
 	for guardPosition := m.guardPosition(); m.contains(&guardPosition); guardPosition = m.nextGuardPosition(&guardPosition) {
 		visited = append(visited, guardPosition)
-		//fmt.Println("Visited:", guardPosition)
-		//fmt.Println(m.String())
 	}
 	return visited
 }
 
-func (m *PatrolMap) PossibleObstructions() []Pos {
+func (m *PatrolMap) PossibleObstructions() []grid.Pos {
 	visitedPositions := m.Clone().VisitGuardPositions()
-	possibleObstructions := make([]Pos, 0)
+	possibleObstructions := make([]grid.Pos, 0)
 	for _, pos := range visitedPositions {
 		if m.DoesObstructionMakeGuardLoop(&pos) {
 			possibleObstructions = append(possibleObstructions, pos)
@@ -91,14 +65,14 @@ func (m *PatrolMap) PossibleObstructions() []Pos {
 	return possibleObstructions
 }
 
-func (m *PatrolMap) DoesObstructionMakeGuardLoop(obstruction *Pos) bool {
+func (m *PatrolMap) DoesObstructionMakeGuardLoop(obstruction *grid.Pos) bool {
 	if m.guardPosition() == *obstruction {
 		return false
 	}
 
 	probeMap := m.Clone()
 	probeMap.setTileAt(obstruction, Obstacle)
-	visited := make(map[Pos][]Direction)
+	visited := make(map[grid.Pos][]Direction)
 
 	for guardPosition := probeMap.guardPosition(); probeMap.contains(&guardPosition); guardPosition = probeMap.nextGuardPosition(&guardPosition) {
 		previousGuardDirectionsOnThisPosition, seen := visited[guardPosition]
@@ -116,57 +90,37 @@ func (m *PatrolMap) DoesObstructionMakeGuardLoop(obstruction *Pos) bool {
 }
 
 func (m *PatrolMap) Clone() *PatrolMap {
-	copiedTiles := make([][]byte, len(m.tiles))
-	for i, row := range m.tiles {
-		copiedTiles[i] = slices.Clone(row)
-	}
-	return &PatrolMap{copiedTiles}
+	return &PatrolMap{m.tiles.Clone()}
 }
 
 func (m *PatrolMap) String() string {
-	sb := strings.Builder{}
-	for _, row := range m.tiles {
-		for _, b := range row {
-			_, _ = fmt.Fprintf(&sb, "%c", b)
-		}
-		_, _ = fmt.Fprintln(&sb)
-	}
-	return sb.String()
+	return m.tiles.String()
 }
 
-func (m *PatrolMap) guardPosition() Pos {
-	for y, row := range m.tiles {
-		for x, tile := range row {
-			if tile == GuardGoingUp || tile == GuardGoingRight || tile == GuardGoingDown || tile == GuardGoingLeft {
-				return Pos{x, y}
-			}
-		}
+func (m *PatrolMap) guardPosition() grid.Pos {
+	isGuard := func(tile byte) bool {
+		return tile == GuardGoingUp || tile == GuardGoingRight || tile == GuardGoingDown || tile == GuardGoingLeft
 	}
-	return Pos{-1, -1}
-}
-
-func (m *PatrolMap) getTileAt(pos *Pos) byte {
-	return m.tiles[pos.y][pos.x]
-}
-
-func (m *PatrolMap) setTileAt(pos *Pos, tile byte) {
-	m.tiles[pos.y][pos.x] = tile
+	if pos, found := m.tiles.Find(isGuard); found {
+		return pos
+	}
+	return grid.Pos{X: -1, Y: -1}
 }
 
-func (m *PatrolMap) contains(pos *Pos) bool {
-	return pos.x >= 0 && pos.x < m.width() && pos.y >= 0 && pos.y < m.height()
+func (m *PatrolMap) getTileAt(pos *grid.Pos) byte {
+	return m.tiles.At(*pos)
 }
 
-func (m *PatrolMap) width() int {
-	return len(m.tiles[0])
+func (m *PatrolMap) setTileAt(pos *grid.Pos, tile byte) {
+	m.tiles.Set(*pos, tile)
 }
 
-func (m *PatrolMap) height() int {
-	return len(m.tiles)
+func (m *PatrolMap) contains(pos *grid.Pos) bool {
+	return m.tiles.Contains(*pos)
 }
 
-func (m *PatrolMap) nextGuardPosition(current *Pos) Pos {
-	var next Pos
+func (m *PatrolMap) nextGuardPosition(current *grid.Pos) grid.Pos {
+	var next grid.Pos
 	guard := m.getTileAt(current)
 	switch guard {
 	case GuardGoingUp:
@@ -195,34 +149,30 @@ func (m *PatrolMap) nextGuardPosition(current *Pos) Pos {
 	return next
 }
 
-//go:embed input.txt
-var input []byte
-
-func main() {
-	patrolMap := PatrolMapFrom(input)
-	visitedPositions := patrolMap.Clone().VisitGuardPositions()
-	fmt.Println("(Part 1) Guard visited", len(visitedPositions), "positions:", visitedPositions)
-
-	occurrences := make(map[Pos]struct{})
+func init() {
+	registry.Register(2024, 6, registry.Solver[*PatrolMap]{
+		Parse: PatrolMapFrom,
+		Part1: func(patrolMap *PatrolMap) (any, error) {
+			visitedPositions := patrolMap.Clone().VisitGuardPositions()
+			slog.Debug("guard visited positions", "count", len(visitedPositions), "positions", visitedPositions)
+			return distinctCount(visitedPositions), nil
+		},
+		Part2: func(patrolMap *PatrolMap) (any, error) {
+			possibleObstructions := patrolMap.PossibleObstructions()
+			slog.Debug("found possible obstructions", "positions", possibleObstructions)
+			return distinctCount(possibleObstructions), nil
+		},
+	})
+}
+
+func distinctCount(positions []grid.Pos) int {
+	occurrences := make(map[grid.Pos]struct{})
 	distinctPositionCount := 0
-	for _, pos := range visitedPositions {
+	for _, pos := range positions {
 		if _, seen := occurrences[pos]; !seen {
 			distinctPositionCount++
 			occurrences[pos] = struct{}{}
 		}
 	}
-	fmt.Println("(Part 1) Guard visited", distinctPositionCount, "distinct positions")
-
-	possibleObstructions := patrolMap.PossibleObstructions()
-	fmt.Println("(Part 2) Possible obstructions:", possibleObstructions)
-
-	clear(occurrences)
-	distinctPossibleObstructionCount := 0
-	for _, pos := range possibleObstructions {
-		if _, seen := occurrences[pos]; !seen {
-			distinctPossibleObstructionCount++
-			occurrences[pos] = struct{}{}
-		}
-	}
-	fmt.Println("(Part 2)", distinctPossibleObstructionCount, "distinct possible obstructions")
+	return distinctPositionCount
 }
@@ -1,10 +1,12 @@
-package main
+package day07
 
 import (
-	_ "embed"
-	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
 type Operator byte
@@ -15,16 +17,16 @@ const (
 	Concatenation  = Operator('|')
 )
 
-func (o *Operator) apply(a, b int) int {
+// apply returns the result of the operator applied to the given operands, or aocmath.ErrOverflow if it does not fit in
+// an int.
+func (o *Operator) apply(a, b int) (int, error) {
 	switch *o {
 	case Addition:
-		return a + b
+		return aocmath.Add(a, b)
 	case Multiplication:
-		return a * b
+		return aocmath.Mul(a, b)
 	case Concatenation:
-		concat := fmt.Sprintf("%d%d", a, b)
-		result, _ := strconv.Atoi(concat)
-		return result
+		return aocmath.ConcatDigits(a, b)
 	}
 	panic("Invalid operator")
 }
@@ -34,28 +36,40 @@ type Equation struct {
 	operands []int
 }
 
-func EquationFrom(value string) *Equation {
-	fields := strings.Split(value, ":")
-	result, _ := strconv.Atoi(fields[0])
-	operands := make([]int, 0)
-	for _, operandValue := range strings.Split(strings.TrimSpace(fields[1]), " ") {
-		operand, _ := strconv.Atoi(operandValue)
-		operands = append(operands, operand)
+func EquationFrom(line parse.Token) (*Equation, error) {
+	resultToken, operandsToken, err := line.Cut(":")
+	if err != nil {
+		return nil, err
+	}
+	result, err := resultToken.Int()
+	if err != nil {
+		return nil, err
+	}
+	operands, err := operandsToken.Ints()
+	if err != nil {
+		return nil, err
 	}
-	return &Equation{result, operands}
+	if len(operands) == 0 {
+		return nil, operandsToken.Errorf("expected at least one operand")
+	}
+	return &Equation{result, operands}, nil
 }
 
 // FindOperators returns the operators that make the equation valid, or nil if no such operators are found.
 func (e *Equation) FindOperators(allowedOperators ...Operator) []Operator {
 	testedOperators := make([]Operator, len(e.operands)-1)
-	combinationCount := pow(len(allowedOperators), len(testedOperators))
+	combinationCount, err := aocmath.Pow(len(allowedOperators), len(testedOperators))
+	if err != nil {
+		// too many combinations to be tested anyway
+		return nil
+	}
 	for combination := range combinationCount {
 		for i := range testedOperators {
-			elephantOperatorIndex := combination / (pow(len(allowedOperators), i)) % len(allowedOperators)
+			elephantOperatorIndex := combination % len(allowedOperators)
 			testedOperators[i] = allowedOperators[elephantOperatorIndex]
+			combination /= len(allowedOperators)
 		}
 		if e.evaluate(testedOperators) {
-			//fmt.Println(e.DebugString(testedOperators))
 			return testedOperators
 		}
 	}
@@ -67,7 +81,11 @@ func (e *Equation) evaluate(operators []Operator) bool {
 	result := e.operands[0]
 	for i, operand := range e.operands[1:] {
 		operator := operators[i]
-		result = operator.apply(result, operand)
+		var err error
+		if result, err = operator.apply(result, operand); err != nil {
+			// an overflowing intermediate result cannot match the expected result
+			return false
+		}
 	}
 	return result == e.result
 }
@@ -84,22 +102,18 @@ func (e *Equation) DebugString(operators []Operator) string {
 	return sb.String()
 }
 
-func pow(a, b int) int {
-	result := 1
-	for range b {
-		result *= a
-	}
-	return result
-}
-
 type Equations []Equation
 
-func EquationsFrom(value string) Equations {
+func EquationsFrom(value string) (Equations, error) {
 	equations := make(Equations, 0)
-	for _, line := range strings.Split(value, "\n") {
-		equations = append(equations, *EquationFrom(line))
+	for _, line := range parse.Lines(value) {
+		equation, err := EquationFrom(line)
+		if err != nil {
+			return nil, err
+		}
+		equations = append(equations, *equation)
 	}
-	return equations
+	return equations, nil
 }
 
 func (e *Equations) TotalCalibrationResult(allowedOperators ...Operator) int {
@@ -112,14 +126,14 @@ func (e *Equations) TotalCalibrationResult(allowedOperators ...Operator) int {
 	return totalCalibrationResult
 }
 
-//go:embed input.txt
-var input string
-
-func main() {
-	equations := EquationsFrom(input)
-	totalCalibrationResult := equations.TotalCalibrationResult(Addition, Multiplication)
-	fmt.Println("(Part 1) Total calibration result:", totalCalibrationResult)
-
-	totalCalibrationResult = equations.TotalCalibrationResult(Addition, Multiplication, Concatenation)
-	fmt.Println("(Part 2) Total calibration result:", totalCalibrationResult)
+func init() {
+	registry.Register(2024, 7, registry.Solver[Equations]{
+		Parse: EquationsFrom,
+		Part1: func(equations Equations) (any, error) {
+			return equations.TotalCalibrationResult(Addition, Multiplication), nil
+		},
+		Part2: func(equations Equations) (any, error) {
+			return equations.TotalCalibrationResult(Addition, Multiplication, Concatenation), nil
+		},
+	})
 }
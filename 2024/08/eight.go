@@ -1,19 +1,19 @@
-package main
+package day08
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"maps"
 	"slices"
-)
 
-type Pos struct {
-	x, y int
-}
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
+)
 
 type AntennaGroup struct {
 	frequency byte
-	positions []Pos
+	positions []grid.Pos
 }
 
 func (group *AntennaGroup) Alignments() []AntennaAlignment {
@@ -26,9 +26,9 @@ func (group *AntennaGroup) Alignments() []AntennaAlignment {
 	return alignments
 }
 
-func (group *AntennaGroup) AntiNodes(maxX, maxY int) []Pos {
+func (group *AntennaGroup) AntiNodes(maxX, maxY int) []grid.Pos {
 	alignments := group.Alignments()
-	antiNodes := make(map[Pos]struct{})
+	antiNodes := make(map[grid.Pos]struct{})
 	for _, alignment := range alignments {
 		for _, antiNode := range alignment.antiNodes(maxX, maxY) {
 			antiNodes[antiNode] = struct{}{}
@@ -37,9 +37,9 @@ func (group *AntennaGroup) AntiNodes(maxX, maxY int) []Pos {
 	return slices.Collect(maps.Keys(antiNodes))
 }
 
-func (group *AntennaGroup) AntiNodesWithResonantHarmonics(maxX, maxY int) []Pos {
+func (group *AntennaGroup) AntiNodesWithResonantHarmonics(maxX, maxY int) []grid.Pos {
 	alignments := group.Alignments()
-	antiNodes := make(map[Pos]struct{})
+	antiNodes := make(map[grid.Pos]struct{})
 	for _, alignment := range alignments {
 		for _, antiNode := range alignment.antiNodesWithResonantHarmonics(maxX, maxY) {
 			antiNodes[antiNode] = struct{}{}
@@ -49,155 +49,138 @@ func (group *AntennaGroup) AntiNodesWithResonantHarmonics(maxX, maxY int) []Pos
 }
 
 type AntennaAlignment struct {
-	antenna1, antenna2 Pos
+	antenna1, antenna2 grid.Pos
 }
 
-func (aa *AntennaAlignment) antiNodes(maxX, maxY int) []Pos {
-	dx := aa.antenna2.x - aa.antenna1.x
-	dy := aa.antenna2.y - aa.antenna1.y
-	antiNode1 := Pos{aa.antenna1.x - dx, aa.antenna1.y - dy}
-	antiNode2 := Pos{aa.antenna2.x + dx, aa.antenna2.y + dy}
-	var antiNodes []Pos
-	if antiNode1.x >= 0 && antiNode1.x <= maxX && antiNode1.y >= 0 && antiNode1.y <= maxY {
+func (aa *AntennaAlignment) antiNodes(maxX, maxY int) []grid.Pos {
+	delta := aa.antenna2.Sub(aa.antenna1)
+	antiNode1 := aa.antenna1.Add(delta.Reverse())
+	antiNode2 := aa.antenna2.Add(delta)
+	var antiNodes []grid.Pos
+	if antiNode1.X >= 0 && antiNode1.X <= maxX && antiNode1.Y >= 0 && antiNode1.Y <= maxY {
 		antiNodes = append(antiNodes, antiNode1)
 	}
-	if antiNode2.x >= 0 && antiNode2.x <= maxX && antiNode2.y >= 0 && antiNode2.y <= maxY {
+	if antiNode2.X >= 0 && antiNode2.X <= maxX && antiNode2.Y >= 0 && antiNode2.Y <= maxY {
 		antiNodes = append(antiNodes, antiNode2)
 	}
 	return antiNodes
 }
 
-func (aa *AntennaAlignment) antiNodesWithResonantHarmonics(maxX, maxY int) []Pos {
-	dx := aa.antenna2.x - aa.antenna1.x
-	dy := aa.antenna2.y - aa.antenna1.y
-
-	divisor := gcd(dx, dy)
-	dx /= divisor
-	dy /= divisor
+func (aa *AntennaAlignment) antiNodesWithResonantHarmonics(maxX, maxY int) []grid.Pos {
+	delta := aa.antenna2.Sub(aa.antenna1)
+	divisor := aocmath.GCD(delta.X, delta.Y)
+	step := grid.Vec{X: delta.X / divisor, Y: delta.Y / divisor}
 
-	var antiNodes []Pos
-	for pos := (Pos{aa.antenna1.x, aa.antenna1.y}); pos.x >= 0 && pos.x <= maxX && pos.y >= 0 && pos.y <= maxY; pos = (Pos{pos.x + dx, pos.y + dy}) {
+	var antiNodes []grid.Pos
+	for pos := aa.antenna1; pos.X >= 0 && pos.X <= maxX && pos.Y >= 0 && pos.Y <= maxY; pos = pos.Add(step) {
 		antiNodes = append(antiNodes, pos)
 	}
-	for pos := (Pos{aa.antenna1.x - dx, aa.antenna1.y - dy}); pos.x >= 0 && pos.x <= maxX && pos.y >= 0 && pos.y <= maxY; pos = (Pos{pos.x - dx, pos.y - dy}) {
+	for pos := aa.antenna1.Add(step.Reverse()); pos.X >= 0 && pos.X <= maxX && pos.Y >= 0 && pos.Y <= maxY; pos = pos.Add(step.Reverse()) {
 		antiNodes = append(antiNodes, pos)
 	}
 
 	return antiNodes
 }
 
-func gcd(a, b int) int {
-	for b != 0 {
-		t := b
-		b = a % b
-		a = t
-	}
-	return a
+type AntennaMap struct {
+	tiles *grid.Grid[byte]
 }
 
-type AntennaMap struct {
-	tiles [][]byte
-}
-
-func AntennaMapFrom(input []byte) AntennaMap {
-	tiles := make([][]byte, 0)
-	currentRow := make([]byte, 0)
-	for _, char := range input {
-		if char == '\n' {
-			tiles = append(tiles, slices.Clone(currentRow))
-			currentRow = currentRow[:0]
-		} else {
-			currentRow = append(currentRow, char)
-		}
-	}
-	if len(currentRow) > 0 {
-		tiles = append(tiles, currentRow)
+func AntennaMapFrom(input string) (AntennaMap, error) {
+	tiles, err := grid.ParseBytes(input)
+	if err != nil {
+		return AntennaMap{}, err
 	}
-	return AntennaMap{tiles}
+	return AntennaMap{tiles}, nil
 }
 
 func (m *AntennaMap) Height() int {
-	return len(m.tiles)
+	return m.tiles.Height()
 }
 
 func (m *AntennaMap) Width() int {
-	return len(m.tiles[0])
+	return m.tiles.Width()
 }
 
 func (m *AntennaMap) AntennaGroups() []AntennaGroup {
 	antenna := make(map[byte]AntennaGroup)
-	for y, row := range m.tiles {
-		for x, tile := range row {
-			if tile != '.' {
-				if group, ok := antenna[tile]; ok {
-					group.positions = append(group.positions, Pos{x, y})
-					antenna[tile] = group
-				} else {
-					antenna[tile] = AntennaGroup{tile, []Pos{{x, y}}}
-				}
+	for pos, tile := range m.tiles.All() {
+		if tile != '.' {
+			if group, ok := antenna[tile]; ok {
+				group.positions = append(group.positions, pos)
+				antenna[tile] = group
+			} else {
+				antenna[tile] = AntennaGroup{tile, []grid.Pos{pos}}
 			}
 		}
 	}
 	return slices.Collect(maps.Values(antenna))
 }
 
-func (m *AntennaMap) PrintAntiNodes() {
-	antennaGroups := m.AntennaGroups()
-	uniqueAntiNodes := make(map[Pos]struct{})
-	for _, group := range antennaGroups {
-		fmt.Printf("Antenna %c\n", group.frequency)
-		fmt.Println("- Positions:", group.positions)
-		fmt.Println("- Alignments:", group.Alignments())
-		antiNodes := group.AntiNodes(m.Width()-1, m.Height()-1)
-		fmt.Println("- Anti-nodes:", antiNodes)
-		for _, antiNode := range antiNodes {
+// DistinctAntiNodes returns the distinct anti-nodes of all the antenna groups of the map.
+func (m *AntennaMap) DistinctAntiNodes() []grid.Pos {
+	uniqueAntiNodes := make(map[grid.Pos]struct{})
+	for _, group := range m.AntennaGroups() {
+		for _, antiNode := range group.AntiNodes(m.Width()-1, m.Height()-1) {
 			uniqueAntiNodes[antiNode] = struct{}{}
 		}
 	}
-	fmt.Println(len(uniqueAntiNodes), "distinct anti-nodes:", slices.Collect(maps.Keys(uniqueAntiNodes)))
-	for y, row := range m.tiles {
-		for x, tile := range row {
-			if _, ok := uniqueAntiNodes[Pos{x, y}]; ok {
-				fmt.Print("#")
-			} else {
-				fmt.Print(string(tile))
-			}
+	return slices.Collect(maps.Keys(uniqueAntiNodes))
+}
+
+// DistinctAntiNodesWithResonantHarmonics returns the distinct anti-nodes with resonant harmonics of all the antenna
+// groups of the map.
+func (m *AntennaMap) DistinctAntiNodesWithResonantHarmonics() []grid.Pos {
+	uniqueAntiNodes := make(map[grid.Pos]struct{})
+	for _, group := range m.AntennaGroups() {
+		for _, antiNode := range group.AntiNodesWithResonantHarmonics(m.Width()-1, m.Height()-1) {
+			uniqueAntiNodes[antiNode] = struct{}{}
 		}
-		fmt.Println()
 	}
+	return slices.Collect(maps.Keys(uniqueAntiNodes))
+}
+
+// LogAntiNodes logs the anti-nodes of each antenna group, then the map with all the distinct anti-nodes.
+func (m *AntennaMap) LogAntiNodes() {
+	m.logAntiNodes("anti-nodes", (*AntennaGroup).AntiNodes)
 }
 
-func (m *AntennaMap) PrintAntiNodesWithResonantHarmonics() {
-	antennaGroups := m.AntennaGroups()
-	uniqueAntiNodes := make(map[Pos]struct{})
-	for _, group := range antennaGroups {
-		fmt.Printf("Antenna %c\n", group.frequency)
-		fmt.Println("- Positions:", group.positions)
-		fmt.Println("- Alignments:", group.Alignments())
-		antiNodes := group.AntiNodesWithResonantHarmonics(m.Width()-1, m.Height()-1)
-		fmt.Println("- Anti-nodes with resonant harmonics:", antiNodes)
+// LogAntiNodesWithResonantHarmonics logs the anti-nodes with resonant harmonics of each antenna group, then the map
+// with all the distinct anti-nodes with resonant harmonics.
+func (m *AntennaMap) LogAntiNodesWithResonantHarmonics() {
+	m.logAntiNodes("anti-nodes with resonant harmonics", (*AntennaGroup).AntiNodesWithResonantHarmonics)
+}
+
+func (m *AntennaMap) logAntiNodes(kind string, antiNodesOf func(group *AntennaGroup, maxX, maxY int) []grid.Pos) {
+	if !report.Enabled(slog.LevelDebug) {
+		return
+	}
+	uniqueAntiNodes := make(map[grid.Pos]struct{})
+	for _, group := range m.AntennaGroups() {
+		antiNodes := antiNodesOf(&group, m.Width()-1, m.Height()-1)
+		slog.Debug("found "+kind, "frequency", string(group.frequency), "positions", group.positions,
+			"alignments", group.Alignments(), "antiNodes", antiNodes)
 		for _, antiNode := range antiNodes {
 			uniqueAntiNodes[antiNode] = struct{}{}
 		}
 	}
-	fmt.Println(len(uniqueAntiNodes), "distinct anti-nodes with resonant harmonics:", slices.Collect(maps.Keys(uniqueAntiNodes)))
-	for y, row := range m.tiles {
-		for x, tile := range row {
-			if _, ok := uniqueAntiNodes[Pos{x, y}]; ok {
-				fmt.Print("#")
-			} else {
-				fmt.Print(string(tile))
-			}
-		}
-		fmt.Println()
+	antiNodesMap := m.tiles.Clone()
+	for antiNode := range uniqueAntiNodes {
+		antiNodesMap.Set(antiNode, '#')
 	}
-}
-
-//go:embed input.txt
-var input []byte
-
-func main() {
-	antennaMap := AntennaMapFrom(input)
-	antennaMap.PrintAntiNodes()
-	antennaMap.PrintAntiNodesWithResonantHarmonics()
+	slog.Debug("found distinct "+kind, "count", len(uniqueAntiNodes), "map", antiNodesMap)
+}
+
+func init() {
+	registry.Register(2024, 8, registry.Solver[AntennaMap]{
+		Parse: AntennaMapFrom,
+		Part1: func(antennaMap AntennaMap) (any, error) {
+			antennaMap.LogAntiNodes()
+			return len(antennaMap.DistinctAntiNodes()), nil
+		},
+		Part2: func(antennaMap AntennaMap) (any, error) {
+			antennaMap.LogAntiNodesWithResonantHarmonics()
+			return len(antennaMap.DistinctAntiNodesWithResonantHarmonics()), nil
+		},
+	})
 }
@@ -1,11 +1,13 @@
-package main
+package day09
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
 type File struct {
@@ -18,18 +20,31 @@ type Disk struct {
 	freeSpaces []byte
 }
 
-func ParseDisk(input string) *Disk {
-	files := make([]File, 0, len(input)/2+1)
-	freeSpaces := make([]byte, 0, len(input)/2)
-	for i, block := range []rune(input) {
+func ParseDisk(input string) (*Disk, error) {
+	lines, err := parse.ExpectLines(input, 1)
+	if err != nil {
+		return nil, err
+	}
+	diskMap := lines[0].Text
+	files := make([]File, 0, len(diskMap)/2+1)
+	freeSpaces := make([]byte, 0, len(diskMap)/2)
+	for i := range len(diskMap) {
+		block := diskMap[i]
+		if block < '0' || block > '9' {
+			return nil, lines[0].Slice(i, i+1).Errorf("expected a digit, got %q", block)
+		}
 		if i%2 == 0 {
-			file := File{id: i / 2, size: byte(block - '0')}
+			file := File{id: i / 2, size: block - '0'}
 			files = append(files, file)
 		} else {
-			freeSpaces = append(freeSpaces, byte(block-'0'))
+			freeSpaces = append(freeSpaces, block-'0')
 		}
 	}
-	return &Disk{files, freeSpaces}
+	return &Disk{files, freeSpaces}, nil
+}
+
+func (d *Disk) Clone() *Disk {
+	return &Disk{slices.Clone(d.files), slices.Clone(d.freeSpaces)}
 }
 
 func (d *Disk) String() string {
@@ -65,7 +80,6 @@ func (d *Disk) Compact() {
 			d.files[len(d.files)-1].size -= freeSpace
 			i++
 		}
-		//fmt.Println(d.String())
 	}
 }
 
@@ -91,7 +105,6 @@ func (d *Disk) CompactFiles() {
 				d.freeSpaces = slices.Insert(d.freeSpaces, freeSpaceIndex+1, freeSpace-fileToMove.size)
 
 				fileToMoveIndex++
-				//fmt.Println(d.String())
 				break
 			}
 		}
@@ -113,18 +126,20 @@ func (d *Disk) Checksum() int {
 	return checksum
 }
 
-//go:embed input.txt
-var input string
-
-func main() {
-	disk := ParseDisk(input)
-
-	disk.Compact()
-	fmt.Println("(Part 1) Compacted disk:", disk)
-	fmt.Println("(Part 1) Checksum:", disk.Checksum())
-
-	disk = ParseDisk(input)
-	disk.CompactFiles()
-	fmt.Println("(Part 2) Compacted disk:", disk)
-	fmt.Println("(Part 2) Checksum:", disk.Checksum())
+func init() {
+	registry.Register(2024, 9, registry.Solver[*Disk]{
+		Parse: ParseDisk,
+		Part1: func(disk *Disk) (any, error) {
+			disk = disk.Clone()
+			disk.Compact()
+			slog.Debug("compacted disk", "disk", disk)
+			return disk.Checksum(), nil
+		},
+		Part2: func(disk *Disk) (any, error) {
+			disk = disk.Clone()
+			disk.CompactFiles()
+			slog.Debug("compacted disk", "disk", disk)
+			return disk.Checksum(), nil
+		},
+	})
 }
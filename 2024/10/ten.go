@@ -1,23 +1,16 @@
-package main
+package day10
 
 import (
-	"bytes"
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"maps"
-)
-
-type Pos struct {
-	x, y int
-}
 
-func (p *Pos) AdjacentPositions() []Pos {
-	return []Pos{{p.x - 1, p.y}, {p.x + 1, p.y}, {p.x, p.y - 1}, {p.x, p.y + 1}}
-}
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
+)
 
-type Trail []Pos
+type Trail []grid.Pos
 
-func (t Trail) Arrival() Pos {
+func (t Trail) Arrival() grid.Pos {
 	return t[len(t)-1]
 }
 
@@ -30,7 +23,7 @@ func (t *TrailHead) AddTrail(trail Trail) {
 }
 
 func (t *TrailHead) Score() int {
-	distinctArrivals := make(map[Pos]struct{})
+	distinctArrivals := make(map[grid.Pos]struct{})
 	for _, trail := range t.trails {
 		distinctArrivals[trail.Arrival()] = struct{}{}
 	}
@@ -42,8 +35,7 @@ func (t *TrailHead) Rating() int {
 }
 
 type TopographicMap struct {
-	levels []byte
-	width  int
+	levels *grid.Grid[int]
 }
 
 const (
@@ -51,51 +43,44 @@ const (
 	EndLevel   = 9
 )
 
-func ParseTopographicMap(input []byte) *TopographicMap {
-	width := bytes.IndexByte(input, '\n')
-	return &TopographicMap{input, width}
+func ParseTopographicMap(input string) (*TopographicMap, error) {
+	levels, err := grid.Parse(input, grid.Digit)
+	if err != nil {
+		return nil, err
+	}
+	return &TopographicMap{levels}, nil
 }
 
-func (t *TopographicMap) TrailHeads() map[Pos]TrailHead {
-	trailHeads := make(map[Pos]TrailHead)
-	for x := range t.width {
-		for y := range t.Height() {
-			if t.LevelAt(x, y) != StartLevel {
-				continue
-			}
-			trails := t.trailsFrom(x, y)
-			for _, trail := range trails {
-				if trailHead, exists := trailHeads[Pos{x, y}]; exists {
-					trailHead.AddTrail(trail)
-					trailHeads[Pos{x, y}] = trailHead
-				} else {
-					trailHeads[Pos{x, y}] = TrailHead{[]Trail{trail}}
-				}
+func (t *TopographicMap) TrailHeads() map[grid.Pos]TrailHead {
+	trailHeads := make(map[grid.Pos]TrailHead)
+	for pos, level := range t.levels.All() {
+		if level != StartLevel {
+			continue
+		}
+		trails := t.trailsFrom(pos)
+		for _, trail := range trails {
+			if trailHead, exists := trailHeads[pos]; exists {
+				trailHead.AddTrail(trail)
+				trailHeads[pos] = trailHead
+			} else {
+				trailHeads[pos] = TrailHead{[]Trail{trail}}
 			}
-
 		}
 	}
 	return trailHeads
 }
 
-func (t *TopographicMap) trailsFrom(x, y int) []Trail {
-	currentPos := Pos{x, y}
-	currentLevel := t.LevelAt(x, y)
+func (t *TopographicMap) trailsFrom(currentPos grid.Pos) []Trail {
+	currentLevel := t.LevelAt(currentPos)
 	if currentLevel == EndLevel {
-		return []Trail{[]Pos{currentPos}}
+		return []Trail{[]grid.Pos{currentPos}}
 	}
 	var trails []Trail
-	for _, nextPos := range currentPos.AdjacentPositions() {
-		if nextPos.x < 0 || nextPos.x >= t.width {
-			continue
-		}
-		if nextPos.y < 0 || nextPos.y >= t.Height() {
-			continue
-		}
-		if t.LevelAt(nextPos.x, nextPos.y) == currentLevel+1 {
-			subTrails := t.trailsFrom(nextPos.x, nextPos.y)
+	for nextPos := range t.levels.Neighbors4(currentPos) {
+		if t.LevelAt(nextPos) == currentLevel+1 {
+			subTrails := t.trailsFrom(nextPos)
 			for _, subTrail := range subTrails {
-				positions := []Pos{currentPos}
+				positions := []grid.Pos{currentPos}
 				positions = append(positions, subTrail...)
 				trail := Trail(positions)
 				trails = append(trails, trail)
@@ -105,39 +90,40 @@ func (t *TopographicMap) trailsFrom(x, y int) []Trail {
 	return trails
 }
 
-func (t *TopographicMap) LevelAt(x, y int) byte {
-	return t.levels[y*(t.width+1)+x] - '0'
+func (t *TopographicMap) LevelAt(pos grid.Pos) int {
+	return t.levels.At(pos)
 }
 
 func (t *TopographicMap) Width() int {
-	return t.width
+	return t.levels.Width()
 }
 
 func (t *TopographicMap) Height() int {
-	return len(t.levels) / t.width
+	return t.levels.Height()
 }
 
 func (t *TopographicMap) String() string {
-	return string(t.levels)
-}
-
-//go:embed input.txt
-var input []byte
-
-func main() {
-	tm := ParseTopographicMap(input)
-
-	trailHeads := tm.TrailHeads()
-	fmt.Println("(Part 1) Trail heads:", trailHeads)
-	scoreSum := 0
-	for trailHead := range maps.Values(trailHeads) {
-		scoreSum += trailHead.Score()
-	}
-	fmt.Println("(Part 1) Score sum:", scoreSum)
-
-	ratingSum := 0
-	for trailHead := range maps.Values(trailHeads) {
-		ratingSum += trailHead.Rating()
-	}
-	fmt.Println("(Part 2) Rating sum:", ratingSum)
+	return t.levels.String()
+}
+
+func init() {
+	registry.Register(2024, 10, registry.Solver[*TopographicMap]{
+		Parse: ParseTopographicMap,
+		Part1: func(tm *TopographicMap) (any, error) {
+			trailHeads := tm.TrailHeads()
+			slog.Debug("found trail heads", "trailHeads", trailHeads)
+			scoreSum := 0
+			for trailHead := range maps.Values(trailHeads) {
+				scoreSum += trailHead.Score()
+			}
+			return scoreSum, nil
+		},
+		Part2: func(tm *TopographicMap) (any, error) {
+			ratingSum := 0
+			for trailHead := range maps.Values(tm.TrailHeads()) {
+				ratingSum += trailHead.Rating()
+			}
+			return ratingSum, nil
+		},
+	})
 }
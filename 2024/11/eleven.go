@@ -1,31 +1,38 @@
-package main
+package day11
 
 import (
-	_ "embed"
-	"fmt"
+	"log/slog"
 	"slices"
-	"strconv"
-	"strings"
+
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
 type Stone int
 
 type Stones []Stone
 
-func StonesFrom(value string) Stones {
+func StonesFrom(value string) (Stones, error) {
+	lines, err := parse.ExpectLines(value, 1)
+	if err != nil {
+		return nil, err
+	}
+	numbers, err := lines[0].Ints()
+	if err != nil {
+		return nil, err
+	}
 	var stones Stones
-	for _, field := range strings.Split(value, " ") {
-		number, _ := strconv.Atoi(field)
+	for _, number := range numbers {
 		stones = append(stones, Stone(number))
 	}
-	return stones
+	return stones, nil
 }
 
 func (s *Stones) Blink(times int) {
 	rules := []Rule{IfZeroThenOne, IfEvenNumberOfDigitsThenTwoStones, ElseMultiplyBy2024}
 	for i := range times {
-		fmt.Println("Current length: ", len(*s))
-		fmt.Println("Blink", i+1)
+		slog.Debug("blinking", "blink", i+1, "stones", len(*s))
 		for i := 0; i < len(*s); i++ {
 			for _, rule := range rules {
 				if j, applied := rule(i, s); applied {
@@ -49,18 +56,14 @@ func IfZeroThenOne(stoneIndex int, stones *Stones) (int, bool) {
 
 func IfEvenNumberOfDigitsThenTwoStones(stoneIndex int, stones *Stones) (int, bool) {
 	stone := (*stones)[stoneIndex]
-	digits := strconv.Itoa(int(stone))
-	if len(digits)%2 != 0 {
+	digitCount := aocmath.DigitCount(stone)
+	if digitCount%2 != 0 {
 		return stoneIndex, false
 	}
 
-	left := digits[:len(digits)/2]
-	leftNumber, _ := strconv.Atoi(left)
-	(*stones)[stoneIndex] = Stone(leftNumber)
-
-	right := digits[len(digits)/2:]
-	rightNumber, _ := strconv.Atoi(right)
-	*stones = slices.Insert(*stones, stoneIndex+1, Stone(rightNumber))
+	left, right := aocmath.SplitDigits(stone, digitCount/2)
+	(*stones)[stoneIndex] = left
+	*stones = slices.Insert(*stones, stoneIndex+1, right)
 
 	return stoneIndex + 1, true
 }
@@ -70,14 +73,18 @@ func ElseMultiplyBy2024(stoneIndex int, stones *Stones) (int, bool) {
 	return stoneIndex, true
 }
 
-//go:embed input.txt
-var input string
-
-func main() {
-	stones := StonesFrom(input)
-	fmt.Println("Initial stones:", stones)
-	stones.Blink(25)
-	fmt.Println(len(stones), "stones after blinking 25 times")
-	stones.Blink(50)
-	fmt.Println(len(stones), "stones after blinking 75 times")
+func init() {
+	registry.Register(2024, 11, registry.Solver[Stones]{
+		Parse: StonesFrom,
+		Part1: func(stones Stones) (any, error) {
+			stones = slices.Clone(stones)
+			stones.Blink(25)
+			return len(stones), nil
+		},
+		Part2: func(stones Stones) (any, error) {
+			stones = slices.Clone(stones)
+			stones.Blink(75)
+			return len(stones), nil
+		},
+	})
 }
@@ -1,53 +1,20 @@
-package main
+package day12
 
 import (
-	_ "embed"
 	"fmt"
+	"log/slog"
 	"slices"
-	"strings"
-)
-
-type Pos struct {
-	x, y int
-}
-
-func (p Pos) AdjacentPositions() []Pos {
-	return []Pos{p.Right(), p.Down(), p.Left(), p.Up()}
-}
-
-func (p Pos) DiagonalPositions() []Pos {
-	return []Pos{p.Right().Up(), p.Right().Down(), p.Left().Down(), p.Left().Up()}
-}
-
-func (p Pos) BorderPositions() []Pos {
-	return append(p.AdjacentPositions(), p.DiagonalPositions()...)
-}
-
-func (p Pos) Right() Pos {
-	return Pos{p.x + 1, p.y}
-}
-
-func (p Pos) Down() Pos {
-	return Pos{p.x, p.y + 1}
-}
-
-func (p Pos) Left() Pos {
-	return Pos{p.x - 1, p.y}
-}
-
-func (p Pos) Up() Pos {
-	return Pos{p.x, p.y - 1}
-}
 
-func (p Pos) IsWithin(width, height int) bool {
-	return p.x >= 0 && p.y >= 0 && p.x < width && p.y < height
-}
+	"github.com/super7ramp/aoc/grid"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
+)
 
 type Plant byte
 
 type Region struct {
 	plant Plant
-	plots []Pos
+	plots []grid.Pos
 }
 
 func (r Region) Area() int {
@@ -57,7 +24,7 @@ func (r Region) Area() int {
 func (r Region) Perimeter() int {
 	perimeter := 0
 	for _, plot := range r.plots {
-		for _, adjacent := range plot.AdjacentPositions() {
+		for _, adjacent := range plot.Neighbors4() {
 			if !slices.Contains(r.plots, adjacent) {
 				perimeter++
 			}
@@ -94,32 +61,29 @@ func (r Region) String() string {
 	return fmt.Sprintf("%c: %v", r.plant, r.plots)
 }
 
-type Garden [][]Plant
+type Garden struct {
+	plants *grid.Grid[Plant]
+}
 
-func GardenFrom(value string) Garden {
-	var garden Garden
-	for _, row := range strings.Split(value, "\n") {
-		garden = append(garden, []Plant(row))
+func GardenFrom(value string) (Garden, error) {
+	plants, err := grid.Parse(value, func(b byte) (Plant, error) { return Plant(b), nil })
+	if err != nil {
+		return Garden{}, err
 	}
-	return garden
+	return Garden{plants}, nil
 }
 
 func (garden Garden) Regions() []Region {
-	var positionsToVisit []Pos
-	for y, row := range garden {
-		for x := range row {
-			positionsToVisit = append(positionsToVisit, Pos{x, y})
-		}
-	}
+	positionsToVisit := slices.Collect(garden.plants.Positions())
 	var regions []Region
 	for len(positionsToVisit) > 0 {
 		plant := garden.PlantAt(positionsToVisit[0])
-		region := Region{plant, []Pos{positionsToVisit[0]}}
+		region := Region{plant, []grid.Pos{positionsToVisit[0]}}
 		positionsToVisit = slices.Delete(positionsToVisit, 0, 1)
 		for i := 0; i < len(region.plots); i++ {
 			current := region.plots[i]
-			for _, adjacent := range current.AdjacentPositions() {
-				if !adjacent.IsWithin(garden.Width(), garden.Height()) || garden.PlantAt(adjacent) != plant {
+			for adjacent := range garden.plants.Neighbors4(current) {
+				if garden.PlantAt(adjacent) != plant {
 					continue
 				}
 				if visitedIndex := slices.Index(positionsToVisit, adjacent); visitedIndex >= 0 {
@@ -133,35 +97,44 @@ func (garden Garden) Regions() []Region {
 	return regions
 }
 
-func (garden Garden) PlantAt(p Pos) Plant {
-	return garden[p.y][p.x]
+func (garden Garden) PlantAt(p grid.Pos) Plant {
+	return garden.plants.At(p)
 }
 
 func (garden Garden) Width() int {
-	return len(garden[0])
+	return garden.plants.Width()
 }
 
 func (garden Garden) Height() int {
-	return len(garden)
-}
-
-//go:embed input-example.txt
-var input string
-
-func main() {
-	garden := GardenFrom(input)
-
-	totalFencingPrice := 0
-	for _, region := range garden.Regions() {
-		fmt.Printf("(Part 1) A region of %c plants with price %d * %d = %d.\n", region.plant, region.Area(), region.Perimeter(), region.FencingPrice())
-		totalFencingPrice += region.FencingPrice()
-	}
-	fmt.Println("(Part 1) The total fencing price is", totalFencingPrice)
-
-	totalFencingPrice = 0
-	for _, region := range garden.Regions() {
-		fmt.Printf("(Part 2) A region of %c plants with price %d * %d = %d.\n", region.plant, region.Area(), region.SideCount(), region.FencingPriceWithBulkDiscount())
-		totalFencingPrice += region.FencingPriceWithBulkDiscount()
-	}
-	fmt.Println("(Part 2) The total fencing price with bulk discount is", totalFencingPrice)
+	return garden.plants.Height()
+}
+
+func init() {
+	registry.Register(2024, 12, registry.Solver[Garden]{
+		Parse: GardenFrom,
+		Part1: func(garden Garden) (any, error) {
+			totalFencingPrice := 0
+			for _, region := range garden.Regions() {
+				price := region.FencingPrice()
+				if report.Enabled(slog.LevelDebug) {
+					slog.Debug("priced region", "plant", string(region.plant), "area", region.Area(), "perimeter",
+						region.Perimeter(), "price", price)
+				}
+				totalFencingPrice += price
+			}
+			return totalFencingPrice, nil
+		},
+		Part2: func(garden Garden) (any, error) {
+			totalFencingPrice := 0
+			for _, region := range garden.Regions() {
+				price := region.FencingPriceWithBulkDiscount()
+				if report.Enabled(slog.LevelDebug) {
+					slog.Debug("priced region", "plant", string(region.plant), "area", region.Area(), "sides",
+						region.SideCount(), "price", price)
+				}
+				totalFencingPrice += price
+			}
+			return totalFencingPrice, nil
+		},
+	})
 }
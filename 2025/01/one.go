@@ -1,14 +1,23 @@
-package main
+package day01
 
 import (
-	_ "embed"
-	"fmt"
-	"strconv"
-	"strings"
+	"github.com/super7ramp/aoc/aocmath"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
 )
 
-//go:embed input.txt
-var input string
+func init() {
+	initialOrientation := Orientation(50)
+	registry.Register(2025, 1, registry.Solver[Rotations]{
+		Parse: ParseRotations,
+		Part1: func(rotations Rotations) (any, error) {
+			return rotations.CountPointedAtZeroFrom(initialOrientation), nil
+		},
+		Part2: func(rotations Rotations) (any, error) {
+			return rotations.CountCrossedZeroFrom(initialOrientation), nil
+		},
+	})
+}
 
 // Orientation represents the current orientation (0-99).
 type Orientation int
@@ -17,28 +26,41 @@ type Orientation int
 // left, positive for turn right), the absolute value indicating the rotation distance.
 type Rotation int
 
-func ParseRotation(input string) Rotation {
+func ParseRotation(line parse.Token) (Rotation, error) {
+	if line.Text == "" {
+		return 0, line.Errorf("empty rotation")
+	}
 	var sign int
-	if input[0] == 'L' {
+	switch line.Text[0] {
+	case 'L':
 		sign = -1
-	} else {
+	case 'R':
 		sign = 1
+	default:
+		return 0, line.Slice(0, 1).Errorf("unknown direction %q, expected 'L' or 'R'", line.Text[0])
+	}
+	if len(line.Text) > 1 && (line.Text[1] == '+' || line.Text[1] == '-') {
+		return 0, line.Slice(1, 2).Errorf("unexpected sign %q, the direction gives the sign", line.Text[1])
 	}
-	value, _ := strconv.Atoi(input[1:])
-	return Rotation(sign * value)
+	value, err := line.Slice(1, len(line.Text)).Int()
+	if err != nil {
+		return 0, err
+	}
+	return Rotation(sign * value), nil
 }
 
 type Rotations []Rotation
 
-func ParseRotations(input string) Rotations {
+func ParseRotations(input string) (Rotations, error) {
 	var instructions Rotations
-	for _, line := range strings.Split(input, "\n") {
-		if len(line) > 1 {
-			instruction := ParseRotation(line)
-			instructions = append(instructions, instruction)
+	for _, line := range parse.Lines(input) {
+		instruction, err := ParseRotation(line)
+		if err != nil {
+			return nil, err
 		}
+		instructions = append(instructions, instruction)
 	}
-	return instructions
+	return instructions, nil
 }
 
 // CountPointedAtZeroFrom applies the rotations starting from the given initial orientation and returns the number of
@@ -47,7 +69,7 @@ func (r Rotations) CountPointedAtZeroFrom(initialOrientation Orientation) int {
 	orientation := int(initialOrientation)
 	pointedAtZeroCount := 0
 	for _, instruction := range r {
-		orientation = mod(orientation+int(instruction), 100)
+		orientation = aocmath.Mod(orientation+int(instruction), 100)
 		if orientation == 0 {
 			pointedAtZeroCount++
 		}
@@ -62,13 +84,13 @@ func (r Rotations) CountCrossedZeroFrom(initialOrientation Orientation) int {
 	crossedZeroCount := 0
 	for _, rotation := range r {
 		crossedZeroCount += countCrossedZero(orientation, rotation)
-		orientation = mod(orientation+int(rotation), 100)
+		orientation = aocmath.Mod(orientation+int(rotation), 100)
 	}
 	return crossedZeroCount
 }
 
 func countCrossedZero(initialOrientation int, rotation Rotation) int {
-	crossedZeroCount := abs(int(rotation)) / 100
+	crossedZeroCount := aocmath.Abs(int(rotation)) / 100
 	rotationLeftover := int(rotation) % 100
 	unboundOrientation := initialOrientation + rotationLeftover
 	if (unboundOrientation <= 0 && initialOrientation != 0) || unboundOrientation >= 100 {
@@ -76,26 +98,3 @@ func countCrossedZero(initialOrientation int, rotation Rotation) int {
 	}
 	return crossedZeroCount
 }
-
-func mod(a, b int) int {
-	return (a%b + b) % b
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
-func main() {
-	fmt.Println("Part 1:")
-	rotations := ParseRotations(input)
-	initialOrientation := Orientation(50)
-	pointedAtZeroCount := rotations.CountPointedAtZeroFrom(initialOrientation)
-	fmt.Printf("Pointed at orientation zero %v times\n", pointedAtZeroCount)
-
-	fmt.Println("Part 2:")
-	crossedZeroCount := rotations.CountCrossedZeroFrom(initialOrientation)
-	fmt.Printf("Crossed orientation zero %v times\n", crossedZeroCount)
-}
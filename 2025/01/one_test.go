@@ -0,0 +1,27 @@
+package day01
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/parse"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, 2025, 1)
+}
+
+func TestParseRotationRejectsSign(t *testing.T) {
+	for _, text := range []string{"R-5", "L+5"} {
+		_, err := ParseRotation(parse.Token{Text: text, Line: 1, Column: 1})
+		var parseErr *parse.Error
+		if !errors.As(err, &parseErr) || parseErr.Column != 2 {
+			t.Errorf("%s: expected a parse error at column 2, got %v", text, err)
+		}
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, 2025, 1)
+}
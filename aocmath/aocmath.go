@@ -0,0 +1,244 @@
+// Package aocmath provides integer arithmetic helpers, which detect overflows instead of silently wrapping around.
+// Functions which may overflow return ErrOverflow; their Big counterparts compute the result with math/big instead.
+package aocmath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Integer is the set of signed integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+var (
+	// ErrOverflow is returned when a result does not fit in the integer type.
+	ErrOverflow = errors.New("integer overflow")
+	// ErrNotInvertible is returned when an integer has no modular inverse.
+	ErrNotInvertible = errors.New("not invertible")
+	// ErrNoSolution is returned when a system of congruences has no solution.
+	ErrNoSolution = errors.New("no solution")
+)
+
+// Abs returns the absolute value of x. Note that the absolute value of the minimal integer is not representable: it is
+// returned unchanged.
+func Abs[T Integer](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Mod returns the Euclidean remainder of a divided by m, i.e. a result in [0, |m|[, unlike the % operator whose result
+// has the sign of a.
+func Mod[T Integer](a, m T) T {
+	r := a % m
+	if r < 0 {
+		r += Abs(m)
+	}
+	return r
+}
+
+// Add returns a + b, or ErrOverflow if the sum does not fit in T.
+func Add[T Integer](a, b T) (T, error) {
+	sum := a + b
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, fmt.Errorf("%w: %d + %d", ErrOverflow, a, b)
+	}
+	return sum, nil
+}
+
+// Mul returns a * b, or ErrOverflow if the product does not fit in T.
+func Mul[T Integer](a, b T) (T, error) {
+	product := a * b
+	if a != 0 && (product/a != b || (a == -1 && b < 0 && product < 0)) {
+		return 0, fmt.Errorf("%w: %d * %d", ErrOverflow, a, b)
+	}
+	return product, nil
+}
+
+// Pow returns base raised to the power exp, or ErrOverflow if the result does not fit in T. It panics if exp is
+// negative.
+func Pow[T Integer](base T, exp int) (T, error) {
+	if exp < 0 {
+		panic("aocmath: negative exponent")
+	}
+	result := T(1)
+	for ; exp > 0; exp >>= 1 {
+		var err error
+		if exp&1 == 1 {
+			if result, err = Mul(result, base); err != nil {
+				return 0, err
+			}
+		}
+		if exp > 1 {
+			if base, err = Mul(base, base); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// GCD returns the greatest common divisor of a and b, which is non-negative. GCD(0, 0) is 0.
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return Abs(a)
+}
+
+// LCM returns the least common multiple of the given values, which is non-negative, or ErrOverflow if it does not fit
+// in T. The LCM of no value is 1; the LCM of values including 0 is 0.
+func LCM[T Integer](values ...T) (T, error) {
+	result := T(1)
+	for _, value := range values {
+		if value == 0 {
+			return 0, nil
+		}
+		var err error
+		// divide before multiplying, so that only a result which does not fit overflows
+		if result, err = Mul(result/GCD(result, value), Abs(value)); err != nil {
+			return 0, err
+		}
+	}
+	return result, nil
+}
+
+// ExtendedGCD returns the greatest common divisor g of a and b, along with Bézout coefficients x and y such that
+// a*x + b*y = g.
+func ExtendedGCD[T Integer](a, b T) (g, x, y T) {
+	oldR, r := a, b
+	oldX, x := T(1), T(0)
+	oldY, y := T(0), T(1)
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldX, x = x, oldX-quotient*x
+		oldY, y = y, oldY-quotient*y
+	}
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// ModInverse returns the inverse of a modulo m, in [0, m[, or ErrNotInvertible if a and m are not coprime. m must be
+// positive.
+func ModInverse[T Integer](a, m T) (T, error) {
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%w: %d modulo %d", ErrNotInvertible, a, m)
+	}
+	return Mod(x, m), nil
+}
+
+// CRT solves the system of congruences x ≡ remainders[i] (mod moduli[i]) using the Chinese remainder theorem. Moduli
+// must be positive but need not be pairwise coprime. It returns the smallest non-negative solution x along with the
+// modulus m of the solution set, i.e. the LCM of the moduli: all the solutions are x + k*m. It returns ErrNoSolution if
+// the congruences are incompatible, ErrOverflow if the computation does not fit in T (see BigCRT).
+func CRT[T Integer](remainders, moduli []T) (x, m T, err error) {
+	if len(remainders) != len(moduli) {
+		panic("aocmath: remainders and moduli differ in length")
+	}
+	x, m = 0, 1
+	for i, modulus := range moduli {
+		if modulus <= 0 {
+			panic("aocmath: non-positive modulus")
+		}
+		remainder := Mod(remainders[i], modulus)
+		g := GCD(m, modulus)
+		if (remainder-x)%g != 0 {
+			return 0, 0, fmt.Errorf("%w: x ≡ %d (mod %d) and x ≡ %d (mod %d)", ErrNoSolution, x, m, remainder, modulus)
+		}
+		// x + m*k ≡ remainder (mod modulus) <=> (m/g)*k ≡ (remainder-x)/g (mod modulus/g)
+		reducedModulus := modulus / g
+		inverse, err := ModInverse(m/g, reducedModulus)
+		if err != nil {
+			return 0, 0, err
+		}
+		k, err := mulMod(Mod((remainder-x)/g, reducedModulus), inverse, reducedModulus)
+		if err != nil {
+			return 0, 0, err
+		}
+		step, err := Mul(m, k)
+		if err != nil {
+			return 0, 0, err
+		}
+		if m, err = Mul(m, reducedModulus); err != nil {
+			return 0, 0, err
+		}
+		if x, err = Add(x, step); err != nil {
+			return 0, 0, err
+		}
+		x = Mod(x, m)
+	}
+	return x, m, nil
+}
+
+func mulMod[T Integer](a, b, m T) (T, error) {
+	product, err := Mul(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return Mod(product, m), nil
+}
+
+// ISqrt returns the integer square root of x, i.e. the greatest integer whose square is at most x. It panics if x is
+// negative.
+func ISqrt[T Integer](x T) T {
+	if x < 0 {
+		panic("aocmath: square root of a negative number")
+	}
+	if x < 2 {
+		return x
+	}
+	// the floating-point estimate may be off by one for large values; the divisions avoid overflowing squares
+	r := T(math.Sqrt(float64(x)))
+	for r > x/r {
+		r--
+	}
+	for r+1 <= x/(r+1) {
+		r++
+	}
+	return r
+}
+
+// DigitCount returns the number of decimal digits of x, ignoring its sign. DigitCount(0) is 1.
+func DigitCount[T Integer](x T) int {
+	count := 1
+	for x /= 10; x != 0; x /= 10 {
+		count++
+	}
+	return count
+}
+
+// SplitDigits splits the decimal digits of x into its n last digits (low) and the remaining leading ones (high), e.g.
+// SplitDigits(1234, 1) returns 123 and 4.
+func SplitDigits[T Integer](x T, n int) (high, low T) {
+	divisor, err := Pow(T(10), n)
+	if err != nil {
+		// 10^n is greater than any x
+		return 0, x
+	}
+	return x / divisor, x % divisor
+}
+
+// ConcatDigits returns the integer whose decimal digits are the ones of a followed by the ones of b, e.g.
+// ConcatDigits(12, 345) returns 12345, or ErrOverflow if the result does not fit in T. b must not be negative.
+func ConcatDigits[T Integer](a, b T) (T, error) {
+	shift, err := Pow(T(10), DigitCount(b))
+	if err != nil {
+		return 0, err
+	}
+	shifted, err := Mul(a, shift)
+	if err != nil {
+		return 0, err
+	}
+	if a < 0 {
+		return Add(shifted, -b)
+	}
+	return Add(shifted, b)
+}
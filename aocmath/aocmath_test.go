@@ -0,0 +1,136 @@
+package aocmath
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMod(t *testing.T) {
+	if actual := Mod(-1, 100); actual != 99 {
+		t.Errorf("expected -1 mod 100 to be 99, got %d", actual)
+	}
+}
+
+func TestMulOverflow(t *testing.T) {
+	tests := []struct{ a, b int64 }{
+		{math.MaxInt64, 2},
+		{math.MinInt64, -1},
+		{-1, math.MinInt64},
+		{1 << 32, 1 << 31},
+	}
+	for _, test := range tests {
+		if _, err := Mul(test.a, test.b); !errors.Is(err, ErrOverflow) {
+			t.Errorf("expected %d * %d to overflow, got %v", test.a, test.b, err)
+		}
+	}
+	if product, err := Mul(int64(-3), 7); err != nil || product != -21 {
+		t.Errorf("expected -21, got %d, %v", product, err)
+	}
+}
+
+func TestPow(t *testing.T) {
+	if result, err := Pow(3, 11); err != nil || result != 177147 {
+		t.Errorf("expected 177147, got %d, %v", result, err)
+	}
+	if _, err := Pow(int32(10), 10); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	if expected := "100000000000000000000"; BigPow(10, 20).String() != expected {
+		t.Errorf("expected %s, got %v", expected, BigPow(10, 20))
+	}
+}
+
+func TestLCM(t *testing.T) {
+	if result, err := LCM(4, 6, 10); err != nil || result != 60 {
+		t.Errorf("expected 60, got %d, %v", result, err)
+	}
+	// 2^62 and 3 do not have common factors, their LCM does not fit
+	if _, err := LCM(int64(1)<<62, 3); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	// the naive a*b/gcd(a,b) overflows, although the LCM fits
+	if result, err := LCM(int64(1)<<62, int64(1)<<61); err != nil || result != int64(1)<<62 {
+		t.Errorf("expected 2^62, got %d, %v", result, err)
+	}
+	if expected := "13835058055282163712"; BigLCM(int64(1)<<62, 3).String() != expected {
+		t.Errorf("expected %s, got %v", expected, BigLCM(int64(1)<<62, 3))
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	if inverse, err := ModInverse(3, 11); err != nil || inverse != 4 {
+		t.Errorf("expected 4, got %d, %v", inverse, err)
+	}
+	if _, err := ModInverse(4, 10); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("expected not invertible, got %v", err)
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name                 string
+		remainders, moduli   []int64
+		expectedX, expectedM int64
+	}{
+		{"coprime moduli", []int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105},
+		{"non-coprime moduli", []int64{3, 5}, []int64{4, 6}, 11, 12},
+		{"negative remainder", []int64{-1}, []int64{5}, 4, 5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, m, err := CRT(test.remainders, test.moduli)
+			if err != nil || x != test.expectedX || m != test.expectedM {
+				t.Errorf("expected %d mod %d, got %d mod %d, %v", test.expectedX, test.expectedM, x, m, err)
+			}
+			bigX, bigM, err := BigCRT(test.remainders, test.moduli)
+			if err != nil || bigX.Int64() != test.expectedX || bigM.Int64() != test.expectedM {
+				t.Errorf("expected %d mod %d, got %v mod %v, %v", test.expectedX, test.expectedM, bigX, bigM, err)
+			}
+		})
+	}
+	if _, _, err := CRT([]int64{1, 2}, []int64{4, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("expected no solution, got %v", err)
+	}
+	if _, _, err := BigCRT([]int64{1, 2}, []int64{4, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("expected no solution, got %v", err)
+	}
+}
+
+func TestISqrt(t *testing.T) {
+	tests := []struct{ x, expected int64 }{
+		{0, 0},
+		{1, 1},
+		{15, 3},
+		{16, 4},
+		{17, 4},
+		{999999999999999999, 999999999},
+		{math.MaxInt64, 3037000499},
+	}
+	for _, test := range tests {
+		if actual := ISqrt(test.x); actual != test.expected {
+			t.Errorf("expected the square root of %d to be %d, got %d", test.x, test.expected, actual)
+		}
+	}
+	if actual := ISqrt(int8(math.MaxInt8)); actual != 11 {
+		t.Errorf("expected 11, got %d", actual)
+	}
+}
+
+func TestDigits(t *testing.T) {
+	if count := DigitCount(0); count != 1 {
+		t.Errorf("expected 1 digit, got %d", count)
+	}
+	if count := DigitCount(-2024); count != 4 {
+		t.Errorf("expected 4 digits, got %d", count)
+	}
+	if high, low := SplitDigits(253000, 3); high != 253 || low != 0 {
+		t.Errorf("expected 253 and 0, got %d and %d", high, low)
+	}
+	if result, err := ConcatDigits(12, 345); err != nil || result != 12345 {
+		t.Errorf("expected 12345, got %d, %v", result, err)
+	}
+	if _, err := ConcatDigits(int64(math.MaxInt64/10), 10); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+}
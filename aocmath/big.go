@@ -0,0 +1,57 @@
+package aocmath
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigPow returns base raised to the power exp, computed with arbitrary precision.
+func BigPow[T Integer](base T, exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(exp)), nil)
+}
+
+// BigLCM returns the least common multiple of the given values, computed with arbitrary precision.
+func BigLCM[T Integer](values ...T) *big.Int {
+	result := big.NewInt(1)
+	for _, value := range values {
+		v := big.NewInt(int64(Abs(value)))
+		if v.Sign() == 0 {
+			return v
+		}
+		gcd := new(big.Int).GCD(nil, nil, result, v)
+		result.Mul(result.Div(result, gcd), v)
+	}
+	return result
+}
+
+// BigCRT is CRT computed with arbitrary precision, for systems whose solution does not fit in T.
+func BigCRT[T Integer](remainders, moduli []T) (x, m *big.Int, err error) {
+	if len(remainders) != len(moduli) {
+		panic("aocmath: remainders and moduli differ in length")
+	}
+	x, m = big.NewInt(0), big.NewInt(1)
+	for i, modulus := range moduli {
+		if modulus <= 0 {
+			panic("aocmath: non-positive modulus")
+		}
+		bigModulus := big.NewInt(int64(modulus))
+		remainder := new(big.Int).Mod(big.NewInt(int64(remainders[i])), bigModulus)
+		g := new(big.Int).GCD(nil, nil, m, bigModulus)
+		difference := new(big.Int).Sub(remainder, x)
+		if new(big.Int).Mod(difference, g).Sign() != 0 {
+			return nil, nil, fmt.Errorf("%w: x ≡ %v (mod %v) and x ≡ %v (mod %v)", ErrNoSolution, x, m, remainder, modulus)
+		}
+		reducedModulus := new(big.Int).Div(bigModulus, g)
+		inverse := new(big.Int).ModInverse(new(big.Int).Div(m, g), reducedModulus)
+		if inverse == nil {
+			// m/g and modulus/g are coprime, so this only happens modulo 1, where any k fits
+			inverse = big.NewInt(0)
+		}
+		k := new(big.Int).Div(difference, g)
+		k.Mul(k, inverse).Mod(k, reducedModulus)
+		x.Add(x, new(big.Int).Mul(m, k))
+		m.Mul(m, reducedModulus)
+		x.Mod(x, m)
+	}
+	return x, m, nil
+}
@@ -0,0 +1,202 @@
+// Package aoctest checks the solutions of the days against their known answers.
+//
+// The known answers of a day are recorded in an answers file in the day directory, next to the inputs. Each line of
+// this file contains the name of an input file, a part number and the expected answer, separated by spaces, e.g.:
+//
+//	# input part answer
+//	input-example.txt 1 3749
+//	input-example.txt 2 11387
+//	input.txt 1 1234567
+//
+// Blank lines and lines starting with '#' are ignored.
+package aoctest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/super7ramp/aoc/input"
+	"github.com/super7ramp/aoc/registry"
+)
+
+// AnswersFileName is the name of the file recording the known answers of a day.
+const AnswersFileName = "answers.txt"
+
+// Answer is the known answer of a part for a given input.
+type Answer struct {
+	Input string
+	Part  int
+	Value string
+}
+
+// ReadAnswers reads the answers file at the given path.
+func ReadAnswers(path string) ([]Answer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var answers []Answer
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: expected '<input> <part> <answer>', got %q", path, lineNumber, line)
+		}
+		part, err := strconv.Atoi(fields[1])
+		if err != nil || part < 1 || part > 2 {
+			return nil, fmt.Errorf("%s:%d: invalid part %q", path, lineNumber, fields[1])
+		}
+		answers = append(answers, Answer{fields[0], part, strings.TrimSpace(fields[2])})
+	}
+	return answers, scanner.Err()
+}
+
+// CheckAnswers checks the solution of the given day against the answers file in the current directory, i.e. the day
+// directory when run from the day's package tests. Answers whose input file is absent, e.g. personal inputs which are
+// not committed, are skipped.
+func CheckAnswers(t *testing.T, year, day int) {
+	t.Helper()
+	solver, ok := registry.Lookup(year, day)
+	if !ok {
+		t.Fatalf("no solution registered for %d day %d", year, day)
+	}
+	answers, err := ReadAnswers(AnswersFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	puzzles := make(map[string]any)
+	for _, answer := range answers {
+		t.Run(fmt.Sprintf("%s/part%d", answer.Input, answer.Part), func(t *testing.T) {
+			puzzle, parsed := puzzles[answer.Input]
+			if !parsed {
+				provider := input.Provider{Path: answer.Input}
+				in, err := provider.Load(year, day)
+				if errors.Is(err, fs.ErrNotExist) {
+					t.Skipf("%s not found", answer.Input)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if puzzle, err = solver.Parse(in); err != nil {
+					t.Fatalf("%v, %s: %v", solver, answer.Input, err)
+				}
+				puzzles[answer.Input] = puzzle
+			}
+
+			var actual any
+			var err error
+			switch {
+			case answer.Part == 1 && solver.HasPart1():
+				actual, err = solver.Part1(puzzle)
+			case answer.Part == 2 && solver.HasPart2():
+				actual, err = solver.Part2(puzzle)
+			default:
+				t.Skipf("part %d is not solved", answer.Part)
+			}
+			if err != nil {
+				t.Fatalf("%v, %s, part %d: %v", solver, answer.Input, answer.Part, err)
+			}
+			if actual := fmt.Sprint(actual); actual != answer.Value {
+				t.Errorf("%v, %s, part %d: expected %s, got %s", solver, answer.Input, answer.Part, answer.Value, actual)
+			}
+		})
+	}
+}
+
+// ParseExample parses the example input in the current directory, i.e. the day directory when run from the day's
+// package tests, with the given parse function. It fails the test if the input cannot be read or parsed.
+func ParseExample[P any](t testing.TB, parse func(input string) (P, error)) P {
+	t.Helper()
+	return ParseFile(t, input.Example.FileName(), parse)
+}
+
+// ParseFile is like ParseExample, for the input file at the given path, e.g. a second example.
+func ParseFile[P any](t testing.TB, path string, parse func(input string) (P, error)) P {
+	t.Helper()
+	in, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	puzzle, err := parse(string(in))
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return puzzle
+}
+
+// Benchmark benchmarks the parsing and the parts of the given day on each input of the answers file in the current
+// directory. Only the parts having a known answer for an input are benchmarked, since the others may not complete in
+// reasonable time.
+func Benchmark(b *testing.B, year, day int) {
+	b.Helper()
+	solver, ok := registry.Lookup(year, day)
+	if !ok {
+		b.Fatalf("no solution registered for %d day %d", year, day)
+	}
+	answers, err := ReadAnswers(AnswersFileName)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var inputNames []string
+	answeredParts := make(map[string][]int)
+	for _, answer := range answers {
+		if _, seen := answeredParts[answer.Input]; !seen {
+			inputNames = append(inputNames, answer.Input)
+		}
+		answeredParts[answer.Input] = append(answeredParts[answer.Input], answer.Part)
+	}
+
+	for _, inputName := range inputNames {
+		provider := input.Provider{Path: inputName}
+		in, err := provider.Load(year, day)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(inputName+"/parse", func(b *testing.B) {
+			for range b.N {
+				solver.Parse(in)
+			}
+		})
+		puzzle, err := solver.Parse(in)
+		if err != nil {
+			b.Fatalf("%v, %s: %v", solver, inputName, err)
+		}
+		for _, part := range answeredParts[inputName] {
+			if part == 1 && solver.HasPart1() {
+				b.Run(inputName+"/part1", func(b *testing.B) {
+					for range b.N {
+						if _, err := solver.Part1(puzzle); err != nil {
+							b.Fatalf("%v, %s, part 1: %v", solver, inputName, err)
+						}
+					}
+				})
+			}
+			if part == 2 && solver.HasPart2() {
+				b.Run(inputName+"/part2", func(b *testing.B) {
+					for range b.N {
+						if _, err := solver.Part2(puzzle); err != nil {
+							b.Fatalf("%v, %s, part 2: %v", solver, inputName, err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Command aoc runs the solutions of the Advent of Code puzzles.
+//
+// Usage:
+//
+//	aoc run [flags] <year> <day>
+//	aoc run [flags] <year> --all
+//	aoc time [flags] <year> <day>
+//	aoc time [flags] <year> --all
+//	aoc new [flags] <year> <day>
+//
+// Flags:
+//
+//	-example        use the example input (input-example.txt) instead of the real one (input.txt)
+//	-input path     read the input from the given path, or from the standard input if path is "-"
+//	-v              print the diagnostics of the solutions on the standard error
+//	-color          render the diagnostics with ANSI colors instead of plain markers
+//	-json           print the answers as JSON records, one per line (run only)
+//	-format format  output format of the timings, either markdown (default) or json (time only)
+//	-part1 answer   answer of part 1 for the example input of the new day (new only)
+//	-part2 answer   answer of part 2 for the example input of the new day (new only)
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/super7ramp/aoc/days"
+	"github.com/super7ramp/aoc/input"
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+	"github.com/super7ramp/aoc/report"
+	"github.com/super7ramp/aoc/scaffold"
+	"github.com/super7ramp/aoc/timing"
+)
+
+const usage = `Usage:
+  aoc run [flags] <year> <day>    runs the solution of the given day
+  aoc run [flags] <year> --all    runs the solutions of all the days of the given year
+  aoc time [flags] <year> <day>   measures the time taken by the solution of the given day
+  aoc time [flags] <year> --all   measures the time taken by the solutions of all the days of the given year
+  aoc new [flags] <year> <day>    creates the skeleton of the given day in the current directory
+
+Flags:
+  -example        use the example input (input-example.txt) instead of the real one (input.txt)
+  -input path     read the input from the given path, or from the standard input if path is "-"
+  -v              print the diagnostics of the solutions on the standard error
+  -color          render the diagnostics with ANSI colors instead of plain markers
+  -json           print the answers as JSON records, one per line (run only)
+  -format format  output format of the timings, either markdown (default) or json (time only)
+  -part1 answer   answer of part 1 for the example input of the new day (new only)
+  -part2 answer   answer of part 2 for the example input of the new day (new only)
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errors.New("missing command")
+	}
+	switch args[0] {
+	case "run":
+		return runCommand(args[1:])
+	case "time":
+		return timeCommand(args[1:])
+	case "new":
+		return newCommand(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	}
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	var selection selection
+	selection.register(flags)
+	var diagnostics diagnostics
+	diagnostics.register(flags)
+	jsonOutput := flags.Bool("json", false, "print the answers as JSON records")
+	days, provider, err := selection.parse(flags, args)
+	if err != nil {
+		return err
+	}
+	logger := diagnostics.newLogger()
+	writer := report.NewTextWriter(os.Stdout)
+	if *jsonOutput {
+		writer = report.NewJSONWriter(os.Stdout)
+	}
+	for _, day := range days {
+		if err := solve(day, provider, logger, writer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func timeCommand(args []string) error {
+	flags := flag.NewFlagSet("time", flag.ContinueOnError)
+	var selection selection
+	selection.register(flags)
+	var diagnostics diagnostics
+	diagnostics.register(flags)
+	format := flags.String("format", "markdown", "output `format`, either markdown or json")
+	days, provider, err := selection.parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	logger := diagnostics.newLogger()
+	timings := make([]timing.Timing, 0, len(days))
+	for _, day := range days {
+		slog.SetDefault(logger.With("year", day.Year, "day", day.Day))
+		in, err := provider.Load(day.Year, day.Day)
+		if err != nil {
+			return err
+		}
+		t, err := timing.Measure(day, in)
+		var partErr *timing.PartError
+		if errors.As(err, &partErr) {
+			return inputError(day, in, fmt.Sprintf("cannot solve part %d", partErr.Part), partErr.Err)
+		}
+		if err != nil {
+			return inputError(day, in, "cannot parse input", err)
+		}
+		timings = append(timings, t)
+	}
+	if *format == "json" {
+		return timing.WriteJSON(os.Stdout, timings)
+	}
+	return timing.WriteMarkdown(os.Stdout, timings)
+}
+
+func newCommand(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	var day scaffold.Day
+	flags.StringVar(&day.ExampleAnswer1, "part1", "", "`answer` of part 1 for the example input")
+	flags.StringVar(&day.ExampleAnswer2, "part2", "", "`answer` of part 2 for the example input")
+	positionals, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positionals) != 2 {
+		return errors.New("expected a year and a day")
+	}
+	if day.Year, err = strconv.Atoi(positionals[0]); err != nil {
+		return fmt.Errorf("invalid year %q", positionals[0])
+	}
+	if day.Day, err = strconv.Atoi(positionals[1]); err != nil {
+		return fmt.Errorf("invalid day %q", positionals[1])
+	}
+	created, err := scaffold.Create(".", day)
+	for _, path := range created {
+		fmt.Println("created", path)
+	}
+	return err
+}
+
+// selection holds the command-line flags selecting the days to run and their input.
+type selection struct {
+	all       bool
+	example   bool
+	inputPath string
+}
+
+func (s *selection) register(flags *flag.FlagSet) {
+	flags.BoolVar(&s.all, "all", false, "run all the days of the year")
+	flags.BoolVar(&s.example, "example", false, "use the example input instead of the real one")
+	flags.StringVar(&s.inputPath, "input", "", "read the input from `path`, or from stdin if path is \"-\"")
+}
+
+// parse parses the given arguments and returns the selected days along with the provider of their input.
+func (s *selection) parse(flags *flag.FlagSet, args []string) ([]*registry.Day, *input.Provider, error) {
+	positionals, err := parseInterspersed(flags, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(positionals) == 0 {
+		return nil, nil, errors.New("missing year")
+	}
+	year, err := strconv.Atoi(positionals[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid year %q", positionals[0])
+	}
+
+	var days []*registry.Day
+	switch {
+	case s.all && len(positionals) == 1:
+		days = registry.Year(year)
+		if len(days) == 0 {
+			return nil, nil, fmt.Errorf("no solution registered for %d", year)
+		}
+	case !s.all && len(positionals) == 2:
+		dayNumber, err := strconv.Atoi(positionals[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid day %q", positionals[1])
+		}
+		day, ok := registry.Lookup(year, dayNumber)
+		if !ok {
+			return nil, nil, fmt.Errorf("no solution registered for %d day %d", year, dayNumber)
+		}
+		days = []*registry.Day{day}
+	default:
+		return nil, nil, errors.New("expected either a day or --all")
+	}
+	if s.inputPath != "" && s.all {
+		return nil, nil, errors.New("-input cannot be used with --all")
+	}
+
+	provider := input.NewProvider(input.Real)
+	provider.Path = s.inputPath
+	if s.example {
+		provider.Kind = input.Example
+	}
+	return days, provider, nil
+}
+
+// parseInterspersed parses the given arguments, allowing flags to appear after positional arguments, and returns the
+// positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positionals []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positionals, nil
+		}
+		positionals = append(positionals, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// diagnostics holds the flags controlling the diagnostics of the solutions.
+type diagnostics struct {
+	verbose bool
+	colored bool
+}
+
+// register registers the diagnostics flags.
+func (d *diagnostics) register(flags *flag.FlagSet) {
+	flags.BoolVar(&d.verbose, "v", false, "print the diagnostics of the solutions on stderr")
+	flags.BoolVar(&d.colored, "color", false, "render the diagnostics with ANSI colors instead of plain markers")
+}
+
+// newLogger returns the logger of the diagnostics of the solutions: they are discarded unless verbose, only warnings
+// and errors being printed.
+func (d *diagnostics) newLogger() *slog.Logger {
+	level := slog.LevelWarn
+	if d.verbose {
+		level = slog.LevelDebug
+	}
+	handler := report.NewHandler(os.Stderr, level)
+	if d.colored {
+		handler = handler.WithColors()
+	}
+	return slog.New(handler)
+}
+
+// solve solves the given day, logging its diagnostics with the given logger, and writes its answers.
+func solve(day *registry.Day, provider *input.Provider, logger *slog.Logger, writer report.Writer) error {
+	slog.SetDefault(logger.With("year", day.Year, "day", day.Day))
+	in, err := provider.Load(day.Year, day.Day)
+	if err != nil {
+		return err
+	}
+	puzzle, err := day.Parse(in)
+	if err != nil {
+		return inputError(day, in, "cannot parse input", err)
+	}
+
+	parts := []struct {
+		number int
+		solved bool
+		solve  func(puzzle any) (any, error)
+	}{
+		{1, day.HasPart1(), day.Part1},
+		{2, day.HasPart2(), day.Part2},
+	}
+	for _, part := range parts {
+		if !part.solved {
+			continue
+		}
+		start := time.Now()
+		answer, err := part.solve(puzzle)
+		duration := time.Since(start)
+		if err != nil {
+			return inputError(day, in, fmt.Sprintf("cannot solve part %d", part.number), err)
+		}
+		slog.Info("solved part", "part", part.number, "duration", duration)
+		err = writer.Write(report.Answer{Year: day.Year, Day: day.Day, Part: part.number, Answer: fmt.Sprint(answer),
+			Duration: duration})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inputError returns an error telling what cannot be done with the input of the given day, quoting the offending line
+// of the input when the error locates it.
+func inputError(day *registry.Day, in string, what string, err error) error {
+	context := parse.Context(in, err)
+	if context == "" {
+		return fmt.Errorf("%v: %s: %w", day, what, err)
+	}
+	return fmt.Errorf("%v: %s: %w\n%s", day, what, err, strings.TrimSuffix(context, "\n"))
+}
@@ -0,0 +1,25 @@
+// Package days registers the solvers of all the days written in Go. Import it for its side effects.
+package days
+
+import (
+	_ "github.com/super7ramp/aoc/2023/01"
+	_ "github.com/super7ramp/aoc/2023/02"
+	_ "github.com/super7ramp/aoc/2023/03"
+	_ "github.com/super7ramp/aoc/2023/05"
+	_ "github.com/super7ramp/aoc/2023/06"
+	_ "github.com/super7ramp/aoc/2023/07"
+	_ "github.com/super7ramp/aoc/2023/08"
+	_ "github.com/super7ramp/aoc/2023/11"
+	_ "github.com/super7ramp/aoc/2023/12"
+	_ "github.com/super7ramp/aoc/2023/17"
+	_ "github.com/super7ramp/aoc/2024/01"
+	_ "github.com/super7ramp/aoc/2024/02"
+	_ "github.com/super7ramp/aoc/2024/06"
+	_ "github.com/super7ramp/aoc/2024/07"
+	_ "github.com/super7ramp/aoc/2024/08"
+	_ "github.com/super7ramp/aoc/2024/09"
+	_ "github.com/super7ramp/aoc/2024/10"
+	_ "github.com/super7ramp/aoc/2024/11"
+	_ "github.com/super7ramp/aoc/2024/12"
+	_ "github.com/super7ramp/aoc/2025/01"
+)
@@ -0,0 +1,3 @@
+module github.com/super7ramp/aoc
+
+go 1.23
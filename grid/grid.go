@@ -0,0 +1,243 @@
+// Package grid provides a generic two-dimensional grid, as found in many puzzles.
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/super7ramp/aoc/parse"
+)
+
+// Grid is a rectangular grid of cells of type T.
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+// New returns a grid of the given dimensions, filled with the zero value of T.
+func New[T any](width, height int) *Grid[T] {
+	return &Grid[T]{width, height, make([]T, width*height)}
+}
+
+// Parse parses a grid from the given input, one row per line, converting each byte to a cell with the given function.
+// Line endings are handled as by parse.Lines. It returns a parse.Error if the lines do not all have the same length or
+// if a byte cannot be converted.
+func Parse[S ~string | ~[]byte, T any](input S, cell func(b byte) (T, error)) (*Grid[T], error) {
+	lines := parse.Lines(string(input))
+	g := &Grid[T]{}
+	if len(lines) == 0 {
+		return g, nil
+	}
+	g.width, g.height = len(lines[0].Text), len(lines)
+	g.cells = make([]T, 0, g.width*g.height)
+	for _, line := range lines {
+		if len(line.Text) != g.width {
+			return nil, line.Errorf("line has length %d, expected %d", len(line.Text), g.width)
+		}
+		for i := range len(line.Text) {
+			c, err := cell(line.Text[i])
+			if err != nil {
+				return nil, &parse.Error{Line: line.Line, Column: i + 1, Err: err}
+			}
+			g.cells = append(g.cells, c)
+		}
+	}
+	return g, nil
+}
+
+// ParseBytes parses a grid of the bytes of the given input, one row per line. See Parse.
+func ParseBytes[S ~string | ~[]byte](input S) (*Grid[byte], error) {
+	return Parse(input, func(b byte) (byte, error) { return b, nil })
+}
+
+// Digit converts a decimal digit to its value. It is meant to be used as a cell function for Parse.
+func Digit(b byte) (int, error) {
+	if b < '0' || b > '9' {
+		return 0, fmt.Errorf("expected a digit, got %q", b)
+	}
+	return int(b - '0'), nil
+}
+
+// OneOf returns a cell function for Parse accepting only the given bytes.
+func OneOf(allowed string) func(b byte) (byte, error) {
+	return func(b byte) (byte, error) {
+		if strings.IndexByte(allowed, b) < 0 {
+			return 0, fmt.Errorf("unexpected %q, expected one of %q", b, allowed)
+		}
+		return b, nil
+	}
+}
+
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// Contains returns true if the given position lies within the grid.
+func (g *Grid[T]) Contains(p Pos) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < g.width && p.Y < g.height
+}
+
+// At returns the cell at the given position. It panics if the position is outside the grid.
+func (g *Grid[T]) At(p Pos) T {
+	return g.cells[g.index(p)]
+}
+
+// Get returns the cell at the given position and true, or the zero value and false if the position is outside the
+// grid.
+func (g *Grid[T]) Get(p Pos) (T, bool) {
+	if !g.Contains(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[g.index(p)], true
+}
+
+// Set sets the cell at the given position. It panics if the position is outside the grid.
+func (g *Grid[T]) Set(p Pos, cell T) {
+	g.cells[g.index(p)] = cell
+}
+
+func (g *Grid[T]) index(p Pos) int {
+	if !g.Contains(p) {
+		panic(fmt.Sprintf("grid: position %v outside of %dx%d grid", p, g.width, g.height))
+	}
+	return p.Y*g.width + p.X
+}
+
+// Row returns the cells of the given row. The returned slice shares its storage with the grid.
+func (g *Grid[T]) Row(y int) []T {
+	return g.cells[y*g.width : (y+1)*g.width : (y+1)*g.width]
+}
+
+// Column returns a copy of the cells of the given column.
+func (g *Grid[T]) Column(x int) []T {
+	column := make([]T, g.height)
+	for y := range g.height {
+		column[y] = g.cells[y*g.width+x]
+	}
+	return column
+}
+
+// All returns an iterator over the positions and cells of the grid, row by row.
+func (g *Grid[T]) All() iter.Seq2[Pos, T] {
+	return func(yield func(Pos, T) bool) {
+		for i, cell := range g.cells {
+			if !yield(Pos{i % g.width, i / g.width}, cell) {
+				return
+			}
+		}
+	}
+}
+
+// Positions returns an iterator over the positions of the grid, row by row.
+func (g *Grid[T]) Positions() iter.Seq[Pos] {
+	return func(yield func(Pos) bool) {
+		for y := range g.height {
+			for x := range g.width {
+				if !yield(Pos{x, y}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Find returns the first position, row by row, whose cell satisfies the given predicate.
+func (g *Grid[T]) Find(predicate func(T) bool) (Pos, bool) {
+	for p, cell := range g.All() {
+		if predicate(cell) {
+			return p, true
+		}
+	}
+	return Pos{}, false
+}
+
+// Neighbors4 returns an iterator over the orthogonally adjacent positions of the given position which lie within the
+// grid.
+func (g *Grid[T]) Neighbors4(p Pos) iter.Seq[Pos] {
+	neighbors := p.Neighbors4()
+	return g.within(neighbors[:])
+}
+
+// Neighbors8 returns an iterator over the orthogonally and diagonally adjacent positions of the given position which
+// lie within the grid.
+func (g *Grid[T]) Neighbors8(p Pos) iter.Seq[Pos] {
+	neighbors := p.Neighbors8()
+	return g.within(neighbors[:])
+}
+
+func (g *Grid[T]) within(positions []Pos) iter.Seq[Pos] {
+	return func(yield func(Pos) bool) {
+		for _, p := range positions {
+			if g.Contains(p) && !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{g.width, g.height, slices.Clone(g.cells)}
+}
+
+// Transpose returns a new grid whose rows are the columns of this grid.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	transposed := New[T](g.height, g.width)
+	for p, cell := range g.All() {
+		transposed.Set(Pos{p.Y, p.X}, cell)
+	}
+	return transposed
+}
+
+// RotateClockwise returns a new grid, rotated by a quarter turn clockwise.
+func (g *Grid[T]) RotateClockwise() *Grid[T] {
+	rotated := New[T](g.height, g.width)
+	for p, cell := range g.All() {
+		rotated.Set(Pos{g.height - 1 - p.Y, p.X}, cell)
+	}
+	return rotated
+}
+
+// RotateCounterClockwise returns a new grid, rotated by a quarter turn counterclockwise.
+func (g *Grid[T]) RotateCounterClockwise() *Grid[T] {
+	rotated := New[T](g.height, g.width)
+	for p, cell := range g.All() {
+		rotated.Set(Pos{p.Y, g.width - 1 - p.X}, cell)
+	}
+	return rotated
+}
+
+// String renders the grid, one row per line. Byte and rune cells are rendered as characters, other cells with their
+// default format.
+func (g *Grid[T]) String() string {
+	sb := strings.Builder{}
+	for y := range g.height {
+		for _, cell := range g.Row(y) {
+			sb.WriteString(render(cell))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func render(cell any) string {
+	if stringer, ok := cell.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	switch value := reflect.ValueOf(cell); value.Kind() {
+	case reflect.Uint8:
+		return string(rune(value.Uint()))
+	case reflect.Int32:
+		return string(rune(value.Int()))
+	default:
+		return fmt.Sprint(cell)
+	}
+}
@@ -0,0 +1,106 @@
+package grid
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/super7ramp/aoc/parse"
+)
+
+const example = "ab.\ncd#\n"
+
+func TestParse(t *testing.T) {
+	g := mustParse(t, example)
+	if g.Width() != 3 || g.Height() != 2 {
+		t.Fatalf("expected 3x2 grid, got %dx%d", g.Width(), g.Height())
+	}
+	if cell := g.At(Pos{2, 1}); cell != '#' {
+		t.Errorf("expected '#' at (2, 1), got %q", cell)
+	}
+	if actual := g.String(); actual != example {
+		t.Errorf("expected %q, got %q", example, actual)
+	}
+}
+
+func TestParseCRLF(t *testing.T) {
+	g := mustParse(t, "ab.\r\ncd#\r\n")
+	if actual := g.String(); actual != example {
+		t.Errorf("expected %q, got %q", example, actual)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		line   int
+		column int
+	}{
+		{"ragged lines", func() error { _, err := ParseBytes([]byte("ab\nc")); return err }(), 2, 1},
+		{"invalid cell", func() error { _, err := Parse("12\n3x", Digit); return err }(), 2, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var parseErr *parse.Error
+			if !errors.As(test.err, &parseErr) {
+				t.Fatalf("expected a parse error, got %v", test.err)
+			}
+			if parseErr.Line != test.line || parseErr.Column != test.column {
+				t.Errorf("expected error at %d:%d, got %v", test.line, test.column, parseErr)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, input string) *Grid[byte] {
+	t.Helper()
+	g, err := ParseBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestTransformations(t *testing.T) {
+	g := mustParse(t, example)
+	tests := []struct {
+		name     string
+		actual   *Grid[byte]
+		expected string
+	}{
+		{"transpose", g.Transpose(), "ac\nbd\n.#\n"},
+		{"rotate clockwise", g.RotateClockwise(), "ca\ndb\n#.\n"},
+		{"rotate counterclockwise", g.RotateCounterClockwise(), ".#\nbd\nac\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.actual.String(); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	g := mustParse(t, example)
+	neighbors4 := slices.Collect(g.Neighbors4(Pos{0, 0}))
+	if expected := []Pos{{1, 0}, {0, 1}}; !slices.Equal(neighbors4, expected) {
+		t.Errorf("expected %v, got %v", expected, neighbors4)
+	}
+	neighbors8 := slices.Collect(g.Neighbors8(Pos{0, 0}))
+	if expected := []Pos{{1, 0}, {1, 1}, {0, 1}}; !slices.Equal(neighbors8, expected) {
+		t.Errorf("expected %v, got %v", expected, neighbors8)
+	}
+}
+
+func TestTurns(t *testing.T) {
+	for i, direction := range Directions4 {
+		if next := Directions4[(i+1)%4]; direction.TurnRight() != next {
+			t.Errorf("expected %v turned right to be %v, got %v", direction, next, direction.TurnRight())
+		}
+		if next := Directions4[(i+3)%4]; direction.TurnLeft() != next {
+			t.Errorf("expected %v turned left to be %v, got %v", direction, next, direction.TurnLeft())
+		}
+	}
+}
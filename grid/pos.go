@@ -0,0 +1,106 @@
+package grid
+
+import "github.com/super7ramp/aoc/aocmath"
+
+// Pos is a position in a grid: X is the column index, Y is the row index, the origin being the top-left corner.
+type Pos struct {
+	X, Y int
+}
+
+// Add returns the position moved by the given vector.
+func (p Pos) Add(v Vec) Pos {
+	return Pos{p.X + v.X, p.Y + v.Y}
+}
+
+// Sub returns the vector going from the given position to this position.
+func (p Pos) Sub(other Pos) Vec {
+	return Vec{p.X - other.X, p.Y - other.Y}
+}
+
+func (p Pos) Up() Pos {
+	return p.Add(Up)
+}
+
+func (p Pos) Down() Pos {
+	return p.Add(Down)
+}
+
+func (p Pos) Left() Pos {
+	return p.Add(Left)
+}
+
+func (p Pos) Right() Pos {
+	return p.Add(Right)
+}
+
+// Neighbors4 returns the four orthogonally adjacent positions, clockwise starting from the one above. They may lie
+// outside a grid, see Grid.Neighbors4 for a bounded version.
+func (p Pos) Neighbors4() [4]Pos {
+	var neighbors [4]Pos
+	for i, direction := range Directions4 {
+		neighbors[i] = p.Add(direction)
+	}
+	return neighbors
+}
+
+// Neighbors8 returns the eight orthogonally and diagonally adjacent positions, clockwise starting from the one above.
+// They may lie outside a grid, see Grid.Neighbors8 for a bounded version.
+func (p Pos) Neighbors8() [8]Pos {
+	var neighbors [8]Pos
+	for i, direction := range Directions8 {
+		neighbors[i] = p.Add(direction)
+	}
+	return neighbors
+}
+
+// ManhattanDistance returns the number of orthogonal steps between the two positions.
+func (p Pos) ManhattanDistance(other Pos) int {
+	return p.Sub(other).ManhattanLength()
+}
+
+// Vec is a displacement in a grid.
+type Vec struct {
+	X, Y int
+}
+
+var (
+	Up        = Vec{0, -1}
+	UpRight   = Vec{1, -1}
+	Right     = Vec{1, 0}
+	DownRight = Vec{1, 1}
+	Down      = Vec{0, 1}
+	DownLeft  = Vec{-1, 1}
+	Left      = Vec{-1, 0}
+	UpLeft    = Vec{-1, -1}
+)
+
+// Directions4 are the orthogonal directions, clockwise starting from Up.
+var Directions4 = [4]Vec{Up, Right, Down, Left}
+
+// Directions8 are the orthogonal and diagonal directions, clockwise starting from Up.
+var Directions8 = [8]Vec{Up, UpRight, Right, DownRight, Down, DownLeft, Left, UpLeft}
+
+// Scale returns the vector multiplied by the given factor.
+func (v Vec) Scale(factor int) Vec {
+	return Vec{v.X * factor, v.Y * factor}
+}
+
+// Reverse returns the vector pointing to the opposite direction.
+func (v Vec) Reverse() Vec {
+	return Vec{-v.X, -v.Y}
+}
+
+// TurnRight returns the vector rotated by a quarter turn clockwise, e.g. Up becomes Right.
+func (v Vec) TurnRight() Vec {
+	return Vec{-v.Y, v.X}
+}
+
+// TurnLeft returns the vector rotated by a quarter turn counterclockwise, e.g. Up becomes Left.
+func (v Vec) TurnLeft() Vec {
+	return Vec{v.Y, -v.X}
+}
+
+// ManhattanLength returns the number of orthogonal steps covered by the vector.
+func (v Vec) ManhattanLength() int {
+	return aocmath.Abs(v.X) + aocmath.Abs(v.Y)
+}
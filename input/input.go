@@ -0,0 +1,118 @@
+// Package input loads the puzzle inputs at runtime.
+//
+// An input is looked up, in order:
+//   - at an explicit path, or on the standard input if the path is "-";
+//   - in the day's directory below each of the search directories, e.g. "2024/06/input.txt";
+//   - in the day's directory below the cache directory, e.g. "~/.cache/aoc/2024/06/input.txt".
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of input of a day: the example given in the puzzle statement or the real, personal input.
+type Kind int
+
+const (
+	Real Kind = iota
+	Example
+)
+
+// FileName returns the conventional name of the file containing this kind of input.
+func (k Kind) FileName() string {
+	if k == Example {
+		return "input-example.txt"
+	}
+	return "input.txt"
+}
+
+func (k Kind) String() string {
+	if k == Example {
+		return "example"
+	}
+	return "real"
+}
+
+// ErrNotFound is returned when an input cannot be found in any of the searched locations.
+var ErrNotFound = errors.New("input not found")
+
+// StdinPath is the path designating the standard input.
+const StdinPath = "-"
+
+// Provider resolves the inputs of the days.
+type Provider struct {
+	// Path is an explicit path to the input. If empty, the input is looked up in the day directories. If StdinPath,
+	// the input is read from Stdin.
+	Path string
+	// Stdin is the reader used when Path is StdinPath.
+	Stdin io.Reader
+	// Dirs are the base directories containing the day directories, searched in order.
+	Dirs []string
+	// Kind is the kind of input to look up in the day directories.
+	Kind Kind
+}
+
+// NewProvider returns a provider looking up inputs of the given kind in the current directory, then in the cache
+// directory.
+func NewProvider(kind Kind) *Provider {
+	return &Provider{Stdin: os.Stdin, Dirs: DefaultDirs(), Kind: kind}
+}
+
+// DefaultDirs returns the current directory, followed by the cache directory if the latter is known.
+func DefaultDirs() []string {
+	dirs := []string{"."}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs, filepath.Join(cacheDir, "aoc"))
+	}
+	return dirs
+}
+
+// DayDir returns the directory of the given day, relative to a base directory, e.g. "2024/06".
+func DayDir(year, day int) string {
+	return filepath.Join(strconv.Itoa(year), fmt.Sprintf("%02d", day))
+}
+
+// Load returns the input of the given day as is, line endings included: they are left to the parsers, see parse.Lines.
+func (p *Provider) Load(year, day int) (string, error) {
+	switch p.Path {
+	case "":
+		return p.lookUp(year, day)
+	case StdinPath:
+		content, err := io.ReadAll(p.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("cannot read input from stdin: %w", err)
+		}
+		return string(content), nil
+	default:
+		content, err := os.ReadFile(p.Path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read input: %w", err)
+		}
+		return string(content), nil
+	}
+}
+
+func (p *Provider) lookUp(year, day int) (string, error) {
+	searched := make([]string, 0, len(p.Dirs))
+	for _, dir := range p.Dirs {
+		path := filepath.Join(dir, DayDir(year, day), p.Kind.FileName())
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			searched = append(searched, path)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("cannot read input: %w", err)
+		}
+		return string(content), nil
+	}
+	return "", fmt.Errorf("%w: no %v input for %d day %d, searched %s", ErrNotFound, p.Kind, year, day,
+		strings.Join(searched, ", "))
+}
@@ -0,0 +1,237 @@
+// Package parse helps parsing puzzle inputs: it splits them into tokens which remember where they come from, so that
+// errors can point at the offending token.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error is an error located in the input. Line and Column are 1-based; a zero Column means the whole line.
+type Error struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Token is a piece of the input, along with its location.
+type Token struct {
+	Text   string
+	Line   int
+	Column int
+}
+
+// Lines splits the given input into lines. Line endings may be either "\n" or "\r\n". A trailing line ending does not
+// start a new line, so that an input ending with a newline has no empty last line. An empty input has no line.
+func Lines(input string) []Token {
+	input = strings.TrimSuffix(strings.TrimSuffix(input, "\n"), "\r")
+	if input == "" {
+		return nil
+	}
+	var lines []Token
+	for i, line := range strings.Split(input, "\n") {
+		lines = append(lines, Token{strings.TrimSuffix(line, "\r"), i + 1, 1})
+	}
+	return lines
+}
+
+// ExpectLines splits the given input into lines, or returns an error if there are not exactly n of them. The error is
+// located at the first extra line, or right after the last line if some are missing.
+func ExpectLines(input string, n int) ([]Token, error) {
+	lines := Lines(input)
+	switch {
+	case len(lines) > n:
+		return nil, lines[n].Errorf("expected %s, got %d", plural(n, "line"), len(lines))
+	case len(lines) < n:
+		return nil, &Error{len(lines) + 1, 0, fmt.Errorf("expected %s, got %d", plural(n, "line"), len(lines))}
+	}
+	return lines, nil
+}
+
+// Blocks splits the given input into blocks of lines separated by empty lines.
+func Blocks(input string) [][]Token {
+	var blocks [][]Token
+	var block []Token
+	for _, line := range Lines(input) {
+		if line.Text != "" {
+			block = append(block, line)
+			continue
+		}
+		if block != nil {
+			blocks = append(blocks, block)
+			block = nil
+		}
+	}
+	if block != nil {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// ExpectBlocks splits the given input into blocks of lines, or returns an error if there are not exactly n of them. The
+// error is located at the first line of the first extra block, or right after the last line if some are missing.
+func ExpectBlocks(input string, n int) ([][]Token, error) {
+	blocks := Blocks(input)
+	switch {
+	case len(blocks) > n:
+		return nil, blocks[n][0].Errorf("expected %s, got %d", plural(n, "block"), len(blocks))
+	case len(blocks) < n:
+		return nil, &Error{len(Lines(input)) + 1, 0, fmt.Errorf("expected %s, got %d", plural(n, "block"), len(blocks))}
+	}
+	return blocks, nil
+}
+
+// plural returns the given count followed by the given noun, in plural if the count is not 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Slice returns the token made of the bytes of this token between the given indices, like t.Text[i:j].
+func (t Token) Slice(i, j int) Token {
+	return Token{t.Text[i:j], t.Line, t.Column + i}
+}
+
+// Fields splits the token around runs of spaces, like strings.Fields.
+func (t Token) Fields() []Token {
+	var fields []Token
+	start := -1
+	for i := 0; i <= len(t.Text); i++ {
+		isSpace := i == len(t.Text) || t.Text[i] == ' ' || t.Text[i] == '\t'
+		switch {
+		case isSpace && start >= 0:
+			fields = append(fields, t.Slice(start, i))
+			start = -1
+		case !isSpace && start < 0:
+			start = i
+		}
+	}
+	return fields
+}
+
+// Split splits the token around each occurrence of the given separator, like strings.Split.
+func (t Token) Split(sep string) []Token {
+	var parts []Token
+	start := 0
+	for {
+		i := strings.Index(t.Text[start:], sep)
+		if i < 0 {
+			return append(parts, t.Slice(start, len(t.Text)))
+		}
+		parts = append(parts, t.Slice(start, start+i))
+		start += i + len(sep)
+	}
+}
+
+// Cut slices the token around the first occurrence of the given separator, like strings.Cut. If the separator is not
+// found, it returns an error located at the end of the token.
+func (t Token) Cut(sep string) (before, after Token, err error) {
+	i := strings.Index(t.Text, sep)
+	if i < 0 {
+		return Token{}, Token{}, t.Slice(len(t.Text), len(t.Text)).Errorf("expected %q", sep)
+	}
+	return t.Slice(0, i), t.Slice(i+len(sep), len(t.Text)), nil
+}
+
+// TrimPrefix returns the token without the given prefix, or an error if the token does not start with it.
+func (t Token) TrimPrefix(prefix string) (Token, error) {
+	if !strings.HasPrefix(t.Text, prefix) {
+		return Token{}, t.Errorf("expected %q", prefix)
+	}
+	return t.Slice(len(prefix), len(t.Text)), nil
+}
+
+// TrimSpace returns the token without its leading and trailing spaces.
+func (t Token) TrimSpace() Token {
+	start := len(t.Text) - len(strings.TrimLeft(t.Text, " \t"))
+	end := len(strings.TrimRight(t.Text, " \t"))
+	if start > end {
+		return t.Slice(start, start)
+	}
+	return t.Slice(start, end)
+}
+
+// Int parses the token as a decimal integer.
+func (t Token) Int() (int, error) {
+	n, err := strconv.Atoi(t.Text)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return 0, t.Errorf("invalid integer %q: %w", t.Text, err)
+	}
+	return n, nil
+}
+
+// Ints parses the space-separated decimal integers of the token.
+func (t Token) Ints() ([]int, error) {
+	fields := t.Fields()
+	ints := make([]int, len(fields))
+	for i, field := range fields {
+		var err error
+		if ints[i], err = field.Int(); err != nil {
+			return nil, err
+		}
+	}
+	return ints, nil
+}
+
+// ExpectFields returns the space-separated fields of the token, or an error if there are not exactly n of them.
+func (t Token) ExpectFields(n int) ([]Token, error) {
+	fields := t.Fields()
+	if len(fields) != n {
+		return nil, t.Errorf("expected %d fields, got %d", n, len(fields))
+	}
+	return fields, nil
+}
+
+// Errorf returns an Error located at the token.
+func (t Token) Errorf(format string, args ...any) error {
+	return &Error{t.Line, t.Column, fmt.Errorf(format, args...)}
+}
+
+// Context returns the line of the given input where the given error is located, with a caret under the faulty column,
+// e.g.:
+//
+//	3 | Game 3: 8 green, x blue
+//	  |                  ^
+//
+// It returns an empty string if the error is not an Error, or if it is located outside the input.
+func Context(input string, err error) string {
+	var parseErr *Error
+	if !errors.As(err, &parseErr) {
+		return ""
+	}
+	lines := Lines(input)
+	if parseErr.Line < 1 || parseErr.Line > len(lines) {
+		return ""
+	}
+	prefix := strconv.Itoa(parseErr.Line) + " | "
+	sb := strings.Builder{}
+	sb.WriteString(prefix)
+	sb.WriteString(lines[parseErr.Line-1].Text)
+	sb.WriteByte('\n')
+	if parseErr.Column > 0 {
+		sb.WriteString(strings.Repeat(" ", len(prefix)-2))
+		sb.WriteString("| ")
+		sb.WriteString(strings.Repeat(" ", parseErr.Column-1))
+		sb.WriteString("^\n")
+	}
+	return sb.String()
+}
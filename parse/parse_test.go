@@ -0,0 +1,97 @@
+package parse
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"empty", "", nil},
+		{"no trailing newline", "a\nb", []string{"a", "b"}},
+		{"trailing newline", "a\nb\n", []string{"a", "b"}},
+		{"crlf", "a\r\nb\r\n", []string{"a", "b"}},
+		{"empty line", "a\n\nb", []string{"a", "", "b"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, line := range Lines(test.input) {
+				actual = append(actual, line.Text)
+			}
+			if !slices.Equal(actual, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	blocks := Blocks("a\nb\n\n\nc\n")
+	if len(blocks) != 2 || len(blocks[0]) != 2 || len(blocks[1]) != 1 {
+		t.Fatalf("expected blocks of 2 and 1 lines, got %v", blocks)
+	}
+	if c := blocks[1][0]; c.Text != "c" || c.Line != 5 {
+		t.Errorf("expected \"c\" at line 5, got %+v", c)
+	}
+}
+
+func TestExpectLines(t *testing.T) {
+	if lines, err := ExpectLines("a\nb\n", 2); err != nil || len(lines) != 2 {
+		t.Errorf("expected 2 lines, got %v, %v", lines, err)
+	}
+	for input, expectedLine := range map[string]int{"a\nb\nc": 3, "a": 2, "": 1} {
+		_, err := ExpectLines(input, 2)
+		var parseErr *Error
+		if !errors.As(err, &parseErr) || parseErr.Line != expectedLine {
+			t.Errorf("%q: expected an error on line %d, got %v", input, expectedLine, err)
+		}
+	}
+}
+
+func TestExpectBlocks(t *testing.T) {
+	if blocks, err := ExpectBlocks("a\n\nb\nc\n", 2); err != nil || len(blocks) != 2 {
+		t.Errorf("expected 2 blocks, got %v, %v", blocks, err)
+	}
+	for input, expectedLine := range map[string]int{"a\n\nb\n\nc": 5, "a\nb\n": 3} {
+		_, err := ExpectBlocks(input, 2)
+		var parseErr *Error
+		if !errors.As(err, &parseErr) || parseErr.Line != expectedLine {
+			t.Errorf("%q: expected an error on line %d, got %v", input, expectedLine, err)
+		}
+	}
+}
+
+func TestTokenLocations(t *testing.T) {
+	line := Lines("x\nGame 12:  3 red, 4 blue")[1]
+	_, grabs, err := line.Cut(":")
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := grabs.Split(",")
+	fields := colors[1].Fields()
+	if expected := (Token{"blue", 2, 20}); fields[1] != expected {
+		t.Errorf("expected %+v, got %+v", expected, fields[1])
+	}
+}
+
+func TestIntError(t *testing.T) {
+	line := Lines("1 2 x3")[0]
+	_, err := line.Ints()
+	var parseErr *Error
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+	if parseErr.Line != 1 || parseErr.Column != 5 {
+		t.Errorf("expected error at 1:5, got %v", err)
+	}
+	expectedContext := "1 | 1 2 x3\n  |     ^\n"
+	if context := Context("1 2 x3", err); context != expectedContext {
+		t.Errorf("expected context %q, got %q", expectedContext, context)
+	}
+}
@@ -0,0 +1,122 @@
+// Package registry references the solvers of the puzzles, so that they can be run from a single command.
+package registry
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Solver describes how to solve a day's puzzle: the input is parsed once into a puzzle of type P, then each part is
+// computed from this puzzle. Parse returns an error if the input is malformed, preferably a parse.Error locating the
+// offending token. Part functions must not modify the puzzle, as it is shared between both parts. They return an error
+// instead of an answer when the puzzle has none, preferably a parse.Error too. A nil part means the part is not solved
+// (yet).
+type Solver[P any] struct {
+	Parse func(input string) (P, error)
+	Part1 func(puzzle P) (any, error)
+	Part2 func(puzzle P) (any, error)
+}
+
+// Day is a registered solver, with its puzzle type erased.
+type Day struct {
+	Year  int
+	Day   int
+	parse func(input string) (any, error)
+	part1 func(puzzle any) (any, error)
+	part2 func(puzzle any) (any, error)
+}
+
+// Parse parses the given input into the day's puzzle.
+func (d *Day) Parse(input string) (any, error) {
+	return d.parse(input)
+}
+
+// HasPart1 returns true if the day has a solution for part 1.
+func (d *Day) HasPart1() bool {
+	return d.part1 != nil
+}
+
+// HasPart2 returns true if the day has a solution for part 2.
+func (d *Day) HasPart2() bool {
+	return d.part2 != nil
+}
+
+// Part1 returns the answer of part 1 for the given parsed puzzle, or the error preventing to find it.
+func (d *Day) Part1(puzzle any) (any, error) {
+	return d.part1(puzzle)
+}
+
+// Part2 returns the answer of part 2 for the given parsed puzzle, or the error preventing to find it.
+func (d *Day) Part2(puzzle any) (any, error) {
+	return d.part2(puzzle)
+}
+
+func (d *Day) String() string {
+	return fmt.Sprintf("%d day %d", d.Year, d.Day)
+}
+
+var (
+	mu   sync.RWMutex
+	days = make(map[int]map[int]*Day)
+)
+
+// Register registers the solver of the given day. It is meant to be called from the init function of the day's
+// package. It panics if a solver is already registered for the same day.
+func Register[P any](year, day int, solver Solver[P]) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := days[year][day]; exists {
+		panic(fmt.Sprintf("registry: solver for %d day %d registered twice", year, day))
+	}
+	if days[year] == nil {
+		days[year] = make(map[int]*Day)
+	}
+	days[year][day] = &Day{
+		Year:  year,
+		Day:   day,
+		parse: func(input string) (any, error) { return solver.Parse(input) },
+		part1: erase(solver.Part1),
+		part2: erase(solver.Part2),
+	}
+}
+
+func erase[P any](part func(puzzle P) (any, error)) func(puzzle any) (any, error) {
+	if part == nil {
+		return nil
+	}
+	return func(puzzle any) (any, error) { return part(puzzle.(P)) }
+}
+
+// Lookup returns the solver registered for the given day, if any.
+func Lookup(year, day int) (*Day, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	d, ok := days[year][day]
+	return d, ok
+}
+
+// Year returns the solvers registered for the given year, sorted by day.
+func Year(year int) []*Day {
+	mu.RLock()
+	defer mu.RUnlock()
+	var yearDays []*Day
+	for _, d := range days[year] {
+		yearDays = append(yearDays, d)
+	}
+	slices.SortFunc(yearDays, func(a, b *Day) int { return cmp.Compare(a.Day, b.Day) })
+	return yearDays
+}
+
+// Years returns the years having at least one registered solver, in ascending order.
+func Years() []int {
+	mu.RLock()
+	defer mu.RUnlock()
+	var years []int
+	for year := range days {
+		years = append(years, year)
+	}
+	slices.Sort(years)
+	return years
+}
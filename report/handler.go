@@ -0,0 +1,113 @@
+package report
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Handler is a slog.Handler writing human-readable records: the level, the message and the attributes on one line.
+// Multi-line attribute values, such as rendered grids, are written below, indented. Styled values are rendered with
+// colors only if the handler is colored. Times are not written.
+type Handler struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	level   slog.Leveler
+	colored bool
+	attrs   []slog.Attr
+	prefix  string
+}
+
+// NewHandler returns a handler writing the records of at least the given level to w.
+func NewHandler(w io.Writer, level slog.Leveler) *Handler {
+	return &Handler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+// WithColors returns a copy of the handler rendering Styled values with ANSI colors.
+func (h *Handler) WithColors() *Handler {
+	clone := *h
+	clone.colored = true
+	return &clone
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	line := strings.Builder{}
+	blocks := strings.Builder{}
+	line.WriteString(record.Level.String())
+	line.WriteByte(' ')
+	line.WriteString(record.Message)
+	write := func(attr slog.Attr) {
+		var value string
+		if styled, isStyled := attr.Value.Any().(Styled); isStyled {
+			value = styled.Render(h.colored)
+		} else {
+			value = attr.Value.String()
+		}
+		if !strings.Contains(value, "\n") {
+			line.WriteString(" " + attr.Key + "=" + value)
+			return
+		}
+		blocks.WriteString("  " + attr.Key + ":\n")
+		for _, valueLine := range strings.Split(strings.TrimSuffix(value, "\n"), "\n") {
+			blocks.WriteString("    " + valueLine + "\n")
+		}
+	}
+	for _, attr := range h.attrs {
+		write(attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		for _, flat := range flatten(h.prefix, attr) {
+			write(flat)
+		}
+		return true
+	})
+	line.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String()+blocks.String())
+	return err
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, flatten(h.prefix, attr)...)
+	}
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// flatten resolves the given attribute and returns it, prefixed, or the attributes of its group, recursively.
+func flatten(prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return []slog.Attr{{Key: prefix + attr.Key, Value: attr.Value}}
+	}
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	var flattened []slog.Attr
+	for _, member := range attr.Value.Group() {
+		flattened = append(flattened, flatten(prefix, member)...)
+	}
+	return flattened
+}
@@ -0,0 +1,73 @@
+// Package report writes the answers of the solutions, either as text or as JSON records, and provides a human-readable
+// log/slog handler for their diagnostics.
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Answer is the answer to a part of a day's puzzle, along with the time taken to compute it.
+type Answer struct {
+	Year     int           `json:"year"`
+	Day      int           `json:"day"`
+	Part     int           `json:"part"`
+	Answer   string        `json:"answer"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Writer writes answers.
+type Writer interface {
+	Write(answer Answer) error
+}
+
+// NewTextWriter returns a writer printing the answers below a header line per day, e.g.:
+//
+//	2024 day 7
+//	  part 1: 3749
+//	  part 2: 11387
+func NewTextWriter(w io.Writer) Writer {
+	return &textWriter{w: w}
+}
+
+type textWriter struct {
+	w         io.Writer
+	year, day int
+}
+
+func (tw *textWriter) Write(answer Answer) error {
+	if answer.Year != tw.year || answer.Day != tw.day {
+		if _, err := fmt.Fprintf(tw.w, "%d day %d\n", answer.Year, answer.Day); err != nil {
+			return err
+		}
+		tw.year, tw.day = answer.Year, answer.Day
+	}
+	_, err := fmt.Fprintf(tw.w, "  part %d: %s\n", answer.Part, answer.Answer)
+	return err
+}
+
+// NewJSONWriter returns a writer printing each answer as a JSON object on its own line, the duration being in
+// nanoseconds, e.g.:
+//
+//	{"year":2024,"day":7,"part":1,"answer":"3749","duration":52125}
+func NewJSONWriter(w io.Writer) Writer {
+	return jsonWriter{json.NewEncoder(w)}
+}
+
+type jsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (jw jsonWriter) Write(answer Answer) error {
+	return jw.encoder.Encode(answer)
+}
+
+// Enabled returns true if the default logger handles records of the given level. It allows to skip computing
+// diagnostics which would be discarded anyway.
+func Enabled(level slog.Level) bool {
+	return slog.Default().Enabled(context.Background(), level)
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestTextWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewTextWriter(&out)
+	for _, answer := range []Answer{{2024, 7, 1, "3749", 0}, {2024, 7, 2, "11387", 0}, {2024, 8, 1, "14", 0}} {
+		if err := w.Write(answer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "2024 day 7\n  part 1: 3749\n  part 2: 11387\n2024 day 8\n  part 1: 14\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewJSONWriter(&out).Write(Answer{2024, 7, 1, "3749", 52 * time.Microsecond}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"year":2024,"day":7,"part":1,"answer":"3749","duration":52000}` + "\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+type styled string
+
+func (s styled) Render(colored bool) string {
+	if colored {
+		return Green.Paint(string(s))
+	}
+	return "[" + string(s) + "]"
+}
+
+func TestHandlerWithColors(t *testing.T) {
+	for colored, expected := range map[bool]string{
+		false: "INFO found galaxy=[#]\n",
+		true:  "INFO found galaxy=\x1b[32m#\x1b[0m\n",
+	} {
+		var out bytes.Buffer
+		handler := NewHandler(&out, slog.LevelInfo)
+		if colored {
+			handler = handler.WithColors()
+		}
+		slog.New(handler).Info("found", "galaxy", styled("#"))
+		if out.String() != expected {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewHandler(&out, slog.LevelInfo)).With("day", 11).WithGroup("universe")
+	logger.Debug("discarded")
+	logger.Info("expanded", "galaxies", 9, "space", "#.\n.#\n")
+	expected := "INFO expanded day=11 universe.galaxies=9\n  universe.space:\n    #.\n    .#\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,26 @@
+package report
+
+// Styled is implemented by diagnostics which can be rendered either with ANSI colors or as plain text, such as
+// annotated grids. The Handler renders them according to its colored option.
+type Styled interface {
+	Render(colored bool) string
+}
+
+// Color is an ANSI Select Graphic Rendition parameter.
+type Color string
+
+const (
+	Bold    Color = "1"
+	Faint   Color = "2"
+	Red     Color = "31"
+	Green   Color = "32"
+	Yellow  Color = "33"
+	Blue    Color = "34"
+	Magenta Color = "35"
+	Cyan    Color = "36"
+)
+
+// Paint returns the given text surrounded by the ANSI escape sequences rendering it with this color.
+func (c Color) Paint(text string) string {
+	return "\x1b[" + string(c) + "m" + text + "\x1b[0m"
+}
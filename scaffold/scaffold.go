@@ -0,0 +1,201 @@
+// Package scaffold creates the skeleton of a new day: its solver, its example input, its answers file and its test, and
+// registers it in the days package.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/super7ramp/aoc/aoctest"
+	"github.com/super7ramp/aoc/input"
+)
+
+// modulePath is the path of the module the days belong to.
+const modulePath = "github.com/super7ramp/aoc"
+
+// ErrExists is returned when asked to create a day which already exists.
+var ErrExists = errors.New("day already exists")
+
+// Day describes the day to create. The example answers are optional: the answers file only has commented placeholders
+// for the missing ones.
+type Day struct {
+	Year           int
+	Day            int
+	ExampleAnswer1 string
+	ExampleAnswer2 string
+}
+
+// Package returns the name of the package of the day, e.g. "day07".
+func (d Day) Package() string {
+	return fmt.Sprintf("day%02d", d.Day)
+}
+
+// FileName returns the base name of the solver file of the day, e.g. "seven", following the existing days.
+func (d Day) FileName() string {
+	return numberNames[d.Day-1]
+}
+
+var numberNames = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven",
+	"twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen", "twenty",
+	"twentyone", "twentytwo", "twentythree", "twentyfour", "twentyfive"}
+
+// Create creates the files of the given day below the given root directory, which must be the root of the module, and
+// registers it in the days package. It returns the paths of the created files. It returns ErrExists without creating
+// anything if the directory of the day already holds any of the files to create, or if the day is already registered.
+func Create(root string, day Day) ([]string, error) {
+	if day.Day < 1 || day.Day > len(numberNames) {
+		return nil, fmt.Errorf("invalid day %d, expected 1 to %d", day.Day, len(numberNames))
+	}
+	dir := filepath.Join(root, input.DayDir(day.Year, day.Day))
+	files := []struct {
+		name     string
+		template *template.Template
+	}{
+		{day.FileName() + ".go", solverTemplate},
+		{day.FileName() + "_test.go", testTemplate},
+		{aoctest.AnswersFileName, answersTemplate},
+		{input.Example.FileName(), nil},
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrExists, path)
+		}
+	}
+	daysPath := filepath.Join(root, "days", "days.go")
+	days, err := os.ReadFile(daysPath)
+	if err != nil {
+		return nil, err
+	}
+	registeredDays, err := register(days, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var created []string
+	for _, file := range files {
+		var content []byte
+		if file.template != nil {
+			if content, err = execute(file.template, day, strings.HasSuffix(file.name, ".go")); err != nil {
+				return created, err
+			}
+		}
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	if err := os.WriteFile(daysPath, registeredDays, 0o644); err != nil {
+		return created, err
+	}
+	return append(created, daysPath), nil
+}
+
+func execute(t *template.Template, day Day, isGo bool) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, day); err != nil {
+		return nil, err
+	}
+	if !isGo {
+		return buffer.Bytes(), nil
+	}
+	return format.Source(buffer.Bytes())
+}
+
+// register returns the given source of the days package with a blank import of the given day, the imports being kept
+// sorted.
+func register(days []byte, day Day) ([]byte, error) {
+	lines := strings.Split(string(days), "\n")
+	start := slices.Index(lines, "import (")
+	if start < 0 {
+		return nil, errors.New("days: import block not found")
+	}
+	closing := slices.Index(lines[start:], ")")
+	if closing < 0 {
+		return nil, errors.New("days: end of import block not found")
+	}
+	end := start + closing
+	importLine := fmt.Sprintf("\t_ %q", modulePath+"/"+input.DayDir(day.Year, day.Day))
+	imports := slices.Clone(lines[start+1 : end])
+	if slices.Contains(imports, importLine) {
+		return nil, fmt.Errorf("%w: %d day %d is registered", ErrExists, day.Year, day.Day)
+	}
+	imports = append(imports, importLine)
+	slices.Sort(imports)
+	registered := slices.Concat(lines[:start+1], imports, lines[end:])
+	return []byte(strings.Join(registered, "\n")), nil
+}
+
+var solverTemplate = template.Must(template.New("solver").Parse(`package {{.Package}}
+
+import (
+	"github.com/super7ramp/aoc/parse"
+	"github.com/super7ramp/aoc/registry"
+)
+
+// Puzzle is the parsed input of the puzzle.
+type Puzzle struct {
+	lines []string
+}
+
+// ParsePuzzle parses the input of the puzzle.
+func ParsePuzzle(input string) (Puzzle, error) {
+	var puzzle Puzzle
+	for _, line := range parse.Lines(input) {
+		puzzle.lines = append(puzzle.lines, line.Text)
+	}
+	return puzzle, nil
+}
+
+// Part1 returns the answer of part 1. It must not modify the puzzle.
+func (puzzle Puzzle) Part1() (any, error) {
+	return nil, nil
+}
+
+// Part2 returns the answer of part 2. It must not modify the puzzle.
+func (puzzle Puzzle) Part2() (any, error) {
+	return nil, nil
+}
+
+func init() {
+	registry.Register({{.Year}}, {{.Day}}, registry.Solver[Puzzle]{
+		Parse: ParsePuzzle,
+		Part1: Puzzle.Part1,
+		Part2: Puzzle.Part2,
+	})
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+	"testing"
+
+	"github.com/super7ramp/aoc/aoctest"
+)
+
+func TestAnswers(t *testing.T) {
+	aoctest.CheckAnswers(t, {{.Year}}, {{.Day}})
+}
+
+func BenchmarkSolve(b *testing.B) {
+	aoctest.Benchmark(b, {{.Year}}, {{.Day}})
+}
+`))
+
+var answersTemplate = template.Must(template.New("answers").Parse(`# input part answer
+{{if .ExampleAnswer1}}input-example.txt 1 {{.ExampleAnswer1}}{{else}}# input-example.txt 1 <answer>{{end}}
+{{if .ExampleAnswer2}}input-example.txt 2 {{.ExampleAnswer2}}{{else}}# input-example.txt 2 <answer>{{end}}
+`))
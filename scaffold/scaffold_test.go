@@ -0,0 +1,100 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const days = `// Package days registers the solvers of all the days written in Go. Import it for its side effects.
+package days
+
+import (
+	_ "github.com/super7ramp/aoc/2024/01"
+	_ "github.com/super7ramp/aoc/2026/01"
+)
+`
+
+func TestCreate(t *testing.T) {
+	root := newRoot(t)
+	created, err := Create(root, Day{Year: 2025, Day: 7, ExampleAnswer1: "21"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 5 {
+		t.Errorf("expected 5 created files, got %v", created)
+	}
+
+	solver := readFile(t, root, "2025/07/seven.go")
+	for _, expected := range []string{"package day07", "registry.Register(2025, 7,"} {
+		if !strings.Contains(solver, expected) {
+			t.Errorf("expected solver to contain %q, got:\n%s", expected, solver)
+		}
+	}
+	answers := readFile(t, root, "2025/07/answers.txt")
+	if expected := "input-example.txt 1 21\n# input-example.txt 2 <answer>\n"; !strings.HasSuffix(answers, expected) {
+		t.Errorf("expected answers to end with %q, got %q", expected, answers)
+	}
+	if example := readFile(t, root, "2025/07/input-example.txt"); example != "" {
+		t.Errorf("expected an empty example input, got %q", example)
+	}
+	registered := readFile(t, root, "days/days.go")
+	expected := strings.Replace(days, "\n\t_ \"github.com/super7ramp/aoc/2026/01\"",
+		"\n\t_ \"github.com/super7ramp/aoc/2025/07\"\n\t_ \"github.com/super7ramp/aoc/2026/01\"", 1)
+	if registered != expected {
+		t.Errorf("expected days.go:\n%s\ngot:\n%s", expected, registered)
+	}
+}
+
+func TestCreateRefusesToOverwrite(t *testing.T) {
+	root := newRoot(t)
+	dir := filepath.Join(root, "2025", "07")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "seven.go"), []byte("package day07\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(root, Day{Year: 2025, Day: 7}); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists, got %v", err)
+	}
+	if _, err := Create(root, Day{Year: 2024, Day: 1}); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists for a registered day, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2024")); err == nil {
+		t.Error("expected nothing to be created for a registered day")
+	}
+	if registered := readFile(t, root, "days/days.go"); registered != days {
+		t.Errorf("expected days.go to be left untouched, got:\n%s", registered)
+	}
+}
+
+func TestRegisterUnclosedImports(t *testing.T) {
+	unclosed := strings.TrimSuffix(days, ")\n")
+	if _, err := register([]byte(unclosed), Day{Year: 2025, Day: 7}); err == nil {
+		t.Error("expected an error for an unclosed import block")
+	}
+}
+
+func newRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "days"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "days", "days.go"), []byte(days), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func readFile(t *testing.T, root, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
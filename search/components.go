@@ -0,0 +1,52 @@
+package search
+
+// StronglyConnectedComponents returns the strongly connected components of the graph made of the given states and of
+// the states reachable from them, using Tarjan's algorithm. Each component lists its states; components are listed in
+// reverse topological order, i.e. a component comes before the components leading to it.
+func StronglyConnectedComponents[S comparable](states []S, neighbors func(S) []S) [][]S {
+	t := tarjan[S]{index: make(map[S]int), lowLink: make(map[S]int), onStack: make(map[S]bool), neighbors: neighbors}
+	for _, state := range states {
+		if _, visited := t.index[state]; !visited {
+			t.visit(state)
+		}
+	}
+	return t.components
+}
+
+type tarjan[S comparable] struct {
+	index      map[S]int
+	lowLink    map[S]int
+	onStack    map[S]bool
+	stack      []S
+	components [][]S
+	neighbors  func(S) []S
+}
+
+func (t *tarjan[S]) visit(state S) {
+	t.index[state] = len(t.index)
+	t.lowLink[state] = t.index[state]
+	t.stack = append(t.stack, state)
+	t.onStack[state] = true
+	for _, neighbor := range t.neighbors(state) {
+		if _, visited := t.index[neighbor]; !visited {
+			t.visit(neighbor)
+			t.lowLink[state] = min(t.lowLink[state], t.lowLink[neighbor])
+		} else if t.onStack[neighbor] {
+			t.lowLink[state] = min(t.lowLink[state], t.index[neighbor])
+		}
+	}
+	if t.lowLink[state] != t.index[state] {
+		return
+	}
+	var component []S
+	for {
+		top := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[top] = false
+		component = append(component, top)
+		if top == state {
+			break
+		}
+	}
+	t.components = append(t.components, component)
+}
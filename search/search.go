@@ -0,0 +1,125 @@
+// Package search finds shortest paths and strongly connected components in implicit graphs, whose nodes are states of
+// any comparable type and whose edges are given by a neighbor function.
+package search
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// Edge is an edge leading to a state, with its cost.
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// Path is a path between two states, along with its total cost.
+type Path[S comparable] struct {
+	// States are the states of the path, from the start to the goal, both included.
+	States []S
+	// Cost is the sum of the costs of the edges of the path; for BFS, it is the number of edges.
+	Cost int
+}
+
+// Dijkstra returns the path with minimal cost from start to the first state satisfying isGoal, or false if no such
+// state is reachable. Edge costs must not be negative.
+func Dijkstra[S comparable](start S, isGoal func(S) bool, neighbors func(S) []Edge[S]) (Path[S], bool) {
+	return AStar(start, isGoal, neighbors, func(S) int { return 0 })
+}
+
+// AStar returns the path with minimal cost from start to the first state satisfying isGoal, or false if no such state
+// is reachable. The heuristic estimates the remaining cost from a state to the goal; it must never overestimate it for
+// the returned path to be minimal. A state reached again with a lower cost is explored again, so that the heuristic
+// needs not be consistent. Edge costs must not be negative.
+func AStar[S comparable](start S, isGoal func(S) bool, neighbors func(S) []Edge[S], heuristic func(S) int) (Path[S], bool) {
+	costs := map[S]int{start: 0}
+	previous := make(map[S]S)
+	frontier := &priorityQueue[S]{{start, 0, heuristic(start)}}
+
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(item[S])
+		if current.cost > costs[current.state] {
+			// stale entry, the state was reached again with a lower cost
+			continue
+		}
+		if isGoal(current.state) {
+			return Path[S]{reconstruct(previous, start, current.state), current.cost}, true
+		}
+		for _, edge := range neighbors(current.state) {
+			cost := current.cost + edge.Cost
+			if knownCost, known := costs[edge.To]; known && knownCost <= cost {
+				continue
+			}
+			costs[edge.To] = cost
+			previous[edge.To] = current.state
+			heap.Push(frontier, item[S]{edge.To, cost, cost + heuristic(edge.To)})
+		}
+	}
+	return Path[S]{}, false
+}
+
+// BFS returns the path with the fewest edges from start to the first state satisfying isGoal, or false if no such
+// state is reachable.
+func BFS[S comparable](start S, isGoal func(S) bool, neighbors func(S) []S) (Path[S], bool) {
+	previous := make(map[S]S)
+	visited := map[S]bool{start: true}
+	frontier := []S{start}
+	for len(frontier) > 0 {
+		current := frontier[0]
+		frontier = frontier[1:]
+		if isGoal(current) {
+			states := reconstruct(previous, start, current)
+			return Path[S]{states, len(states) - 1}, true
+		}
+		for _, neighbor := range neighbors(current) {
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				previous[neighbor] = current
+				frontier = append(frontier, neighbor)
+			}
+		}
+	}
+	return Path[S]{}, false
+}
+
+func reconstruct[S comparable](previous map[S]S, start, goal S) []S {
+	states := []S{goal}
+	for current := goal; current != start; {
+		current = previous[current]
+		states = append(states, current)
+	}
+	slices.Reverse(states)
+	return states
+}
+
+type item[S comparable] struct {
+	state    S
+	cost     int
+	priority int
+}
+
+// priorityQueue implements heap.Interface, the item with the lowest priority being on top.
+type priorityQueue[S comparable] []item[S]
+
+func (q priorityQueue[S]) Len() int {
+	return len(q)
+}
+
+func (q priorityQueue[S]) Less(i, j int) bool {
+	return q[i].priority < q[j].priority
+}
+
+func (q priorityQueue[S]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *priorityQueue[S]) Push(x any) {
+	*q = append(*q, x.(item[S]))
+}
+
+func (q *priorityQueue[S]) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
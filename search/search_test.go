@@ -0,0 +1,111 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+// a -1-> b -1-> c -1-> d
+// a -----------5-----> d
+var graph = map[string][]Edge[string]{
+	"a": {{"d", 5}, {"b", 1}},
+	"b": {{"c", 1}},
+	"c": {{"d", 1}},
+	"e": {{"a", 1}},
+}
+
+func neighbors(s string) []Edge[string] {
+	return graph[s]
+}
+
+func unweightedNeighbors(s string) []string {
+	var states []string
+	for _, edge := range graph[s] {
+		states = append(states, edge.To)
+	}
+	return states
+}
+
+func is(goal string) func(string) bool {
+	return func(s string) bool { return s == goal }
+}
+
+func TestDijkstra(t *testing.T) {
+	path, found := Dijkstra("a", is("d"), neighbors)
+	if !found {
+		t.Fatal("expected a path")
+	}
+	if expected := []string{"a", "b", "c", "d"}; !slices.Equal(path.States, expected) || path.Cost != 3 {
+		t.Errorf("expected %v with cost 3, got %v with cost %d", expected, path.States, path.Cost)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	remaining := map[string]int{"a": 3, "b": 2, "c": 1, "d": 0}
+	path, found := AStar("a", is("d"), neighbors, func(s string) int { return remaining[s] })
+	if !found {
+		t.Fatal("expected a path")
+	}
+	if expected := []string{"a", "b", "c", "d"}; !slices.Equal(path.States, expected) || path.Cost != 3 {
+		t.Errorf("expected %v with cost 3, got %v with cost %d", expected, path.States, path.Cost)
+	}
+}
+
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	// s -1-> a -1-> b -3-> g
+	// s -----3----> b
+	// the heuristic never overestimates, but makes b explored through the costlier edge before a
+	inconsistent := map[string][]Edge[string]{
+		"s": {{"a", 1}, {"b", 3}},
+		"a": {{"b", 1}},
+		"b": {{"g", 3}},
+	}
+	remaining := map[string]int{"a": 4}
+	path, found := AStar("s", is("g"), func(s string) []Edge[string] { return inconsistent[s] },
+		func(s string) int { return remaining[s] })
+	if !found {
+		t.Fatal("expected a path")
+	}
+	if expected := []string{"s", "a", "b", "g"}; !slices.Equal(path.States, expected) || path.Cost != 5 {
+		t.Errorf("expected %v with cost 5, got %v with cost %d", expected, path.States, path.Cost)
+	}
+}
+
+func TestBFS(t *testing.T) {
+	path, found := BFS("a", is("d"), unweightedNeighbors)
+	if !found {
+		t.Fatal("expected a path")
+	}
+	if expected := []string{"a", "d"}; !slices.Equal(path.States, expected) || path.Cost != 1 {
+		t.Errorf("expected %v with cost 1, got %v with cost %d", expected, path.States, path.Cost)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	if _, found := Dijkstra("a", is("e"), neighbors); found {
+		t.Error("expected no path with Dijkstra")
+	}
+	if _, found := BFS("a", is("e"), unweightedNeighbors); found {
+		t.Error("expected no path with BFS")
+	}
+}
+
+func TestStartIsGoal(t *testing.T) {
+	path, found := Dijkstra("a", is("a"), neighbors)
+	if !found || !slices.Equal(path.States, []string{"a"}) || path.Cost != 0 {
+		t.Errorf("expected path [a] with cost 0, got %v with cost %d", path.States, path.Cost)
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	// a <-> b -> c -> d -> c, e alone
+	cyclic := map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"d"}, "d": {"c"}}
+	components := StronglyConnectedComponents([]string{"a", "e"}, func(s string) []string { return cyclic[s] })
+	for _, component := range components {
+		slices.Sort(component)
+	}
+	expected := [][]string{{"c", "d"}, {"a", "b"}, {"e"}}
+	if !slices.EqualFunc(components, expected, slices.Equal) {
+		t.Errorf("expected %v, got %v", expected, components)
+	}
+}
@@ -0,0 +1,103 @@
+// Package timing measures the time taken by the solutions and reports it as a table.
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/super7ramp/aoc/registry"
+)
+
+// Timing is the time taken to parse the input and to solve each part of a day. A zero duration means the part is not
+// solved.
+type Timing struct {
+	Year  int           `json:"year"`
+	Day   int           `json:"day"`
+	Parse time.Duration `json:"parse_ns"`
+	Part1 time.Duration `json:"part1_ns,omitempty"`
+	Part2 time.Duration `json:"part2_ns,omitempty"`
+}
+
+// Total returns the total time taken by the day.
+func (t *Timing) Total() time.Duration {
+	return t.Parse + t.Part1 + t.Part2
+}
+
+// PartError is the error a part returned instead of an answer.
+type PartError struct {
+	Part int
+	Err  error
+}
+
+func (e *PartError) Error() string {
+	return fmt.Sprintf("part %d: %v", e.Part, e.Err)
+}
+
+func (e *PartError) Unwrap() error {
+	return e.Err
+}
+
+// Measure measures the time taken to parse the given input and to solve each part of the given day. It returns an
+// error if the input cannot be parsed, or a *PartError if a part cannot be solved.
+func Measure(day *registry.Day, input string) (Timing, error) {
+	timing := Timing{Year: day.Year, Day: day.Day}
+
+	start := time.Now()
+	puzzle, err := day.Parse(input)
+	if err != nil {
+		return timing, err
+	}
+	timing.Parse = time.Since(start)
+
+	if day.HasPart1() {
+		start = time.Now()
+		_, err := day.Part1(puzzle)
+		timing.Part1 = time.Since(start)
+		if err != nil {
+			return timing, &PartError{1, err}
+		}
+	}
+	if day.HasPart2() {
+		start = time.Now()
+		_, err := day.Part2(puzzle)
+		timing.Part2 = time.Since(start)
+		if err != nil {
+			return timing, &PartError{2, err}
+		}
+	}
+	return timing, nil
+}
+
+// WriteMarkdown writes the given timings as a markdown table.
+func WriteMarkdown(w io.Writer, timings []Timing) error {
+	if _, err := fmt.Fprintln(w, "| Year | Day | Parse | Part 1 | Part 2 | Total |"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "|-----:|----:|------:|-------:|-------:|------:|"); err != nil {
+		return err
+	}
+	for _, t := range timings {
+		_, err := fmt.Fprintf(w, "| %d | %d | %v | %v | %v | %v |\n", t.Year, t.Day, t.Parse, cell(t.Part1),
+			cell(t.Part2), t.Total())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cell(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.String()
+}
+
+// WriteJSON writes the given timings as a JSON array, durations being expressed in nanoseconds.
+func WriteJSON(w io.Writer, timings []Timing) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(timings)
+}